@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"hockey-hacks/pkg/yahoo"
+)
+
+func runAdd(args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	add := fs.String("add", "", "Yahoo player key to add, e.g. 465.p.1234")
+	drop := fs.String("drop", "", "Yahoo player key to drop")
+	faab := fs.Int("faab", -1, "FAAB bid for a waiver claim")
+	team := fs.String("team", "", "Yahoo team key (default: first configured team)")
+	fs.Parse(args)
+
+	if *add == "" && *drop == "" {
+		return errors.New("usage: hockey-hacks add [-add key] [-drop key] [-faab bid] [-team key]")
+	}
+
+	yc, teamKey, err := yahooClient(*team)
+	if err != nil {
+		return err
+	}
+
+	req := yahoo.TransactionRequest{Add: *add, Drop: *drop}
+	if *faab >= 0 {
+		req.FAABBid = faab
+	}
+	tr, err := yc.Transact(teamKey, req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s: %s\n", tr.Type, tr.TransactionKey, tr.Status)
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"hockey-hacks/pkg/budget"
+	"hockey-hacks/pkg/clock"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"os"
+	"time"
+)
+
+func runBudget(args []string) error {
+	fs := flag.NewFlagSet("budget", flag.ExitOnError)
+	team := fs.String("team", "", "Yahoo team key (default: first configured team)")
+	dateFlag := fs.String("date", "", "Forecast the rest of the week from this date, as YYYY-MM-DD (default today)")
+	fs.Parse(args)
+
+	date := clock.Today(clock.Real{})
+	if *dateFlag != "" {
+		var err error
+		if date, err = clock.ParseDate(clock.Real{}, *dateFlag); err != nil {
+			return err
+		}
+	}
+
+	yc, teamKey, err := yahooClient(*team)
+	if err != nil {
+		return err
+	}
+	league, err := yc.GetLeague(yahoo.LeagueKey(teamKey))
+	if err != nil {
+		return err
+	}
+	b, err := yc.GetAddBudget(teamKey)
+	if err != nil {
+		return err
+	}
+	weeks, err := yc.GetGameWeeks(yahoo.GameKey(teamKey))
+	if err != nil {
+		return err
+	}
+	week, err := yahoo.WeekOf(weeks, date)
+	if err != nil {
+		return err
+	}
+	roster, err := yc.GetRosterPlayers(teamKey, yahoo.DateCoverage(date))
+	if err != nil {
+		return err
+	}
+
+	weekDates, err := week.Dates(date.Location())
+	if err != nil {
+		return err
+	}
+	sd := sportsData.NewClient(false)
+	var dates []time.Time
+	var games []sportsData.Games
+	for _, d := range weekDates {
+		if d.Before(date) {
+			continue
+		}
+		g, err := sd.GetGames(d)
+		if err != nil {
+			return err
+		}
+		dates = append(dates, d)
+		games = append(games, g)
+	}
+
+	return budget.WriteReport(os.Stdout, teamKey, b, budget.Forecast(roster, league.Settings, dates, games))
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hockey-hacks/pkg/audit"
+	"hockey-hacks/pkg/yahoo"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	team := fs.String("team", "", "Yahoo team key whose league to sync (default: first configured team)")
+	from := fs.String("from", "", "Show entries on or after this date (YYYY-MM-DD)")
+	to := fs.String("to", "", "Show entries on or before this date (YYYY-MM-DD)")
+	player := fs.String("player", "", "Show entries for a player key or name")
+	kind := fs.String("type", "", "Show entries of a type, e.g. add, drop, add/drop, trade, lineup date")
+	sync := fs.Bool("sync", true, "Fetch the league's transactions into the log first")
+	path := fs.String("file", audit.Path(), "Audit log path")
+	fs.Parse(args)
+
+	filter := audit.Filter{Player: *player, Type: *kind}
+	if *from != "" {
+		t, err := time.ParseInLocation(time.DateOnly, *from, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid -from date: %w", err)
+		}
+		filter.From = t
+	}
+	if *to != "" {
+		t, err := time.ParseInLocation(time.DateOnly, *to, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid -to date: %w", err)
+		}
+		filter.To = t.AddDate(0, 0, 1)
+	}
+
+	auditLog := audit.New(*path)
+	if *sync {
+		if err := syncTransactions(auditLog, *team); err != nil {
+			return err
+		}
+	}
+
+	entries, err := auditLog.Entries()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tSOURCE\tTYPE\tTEAM\tPLAYERS\tSTATUS")
+	for _, e := range entries {
+		if !filter.Match(e) {
+			continue
+		}
+		var players []string
+		for _, p := range e.Players {
+			name := p.Name
+			if name == "" {
+				name = p.Key
+			}
+			if p.Detail != "" {
+				name += " (" + p.Detail + ")"
+			}
+			players = append(players, name)
+		}
+		status := e.Status
+		if e.Error != "" {
+			status = "error: " + e.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Time.Local().Format("2006-01-02 15:04"), e.Source, e.Type, e.TeamKey, strings.Join(players, ", "), status)
+	}
+	return w.Flush()
+}
+
+// syncTransactions appends the league transactions the log does not have
+// yet, oldest first.
+func syncTransactions(auditLog *audit.Log, teamKey string) error {
+	yc, teamKey, err := yahooClient(teamKey)
+	if err != nil {
+		return err
+	}
+	transactions, err := yc.GetTransactions(yahoo.LeagueKey(teamKey))
+	if err != nil {
+		return err
+	}
+	entries, err := auditLog.Entries()
+	if err != nil {
+		return err
+	}
+	logged := make(map[string]bool)
+	for _, e := range entries {
+		if e.Source == audit.SourceLeague {
+			logged[e.TransactionKey] = true
+		}
+	}
+
+	var added []audit.Entry
+	for i := len(transactions) - 1; i >= 0; i-- {
+		if t := transactions[i]; !logged[t.TransactionKey] {
+			added = append(added, t.AuditEntry())
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	log.Printf("Logged %d new league transactions", len(added))
+	return auditLog.Append(added...)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"hockey-hacks/pkg/config"
+	"hockey-hacks/pkg/yahoo"
+	"io"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "add", usage: "add, drop or claim players for a team", run: runAdd},
+	{name: "budget", usage: "show the weekly add budget and the best days to use it", run: runBudget},
+	{name: "history", usage: "show league transactions and the changes this tool made", run: runHistory},
+	{name: "matchup", usage: "show the current matchup category by category", run: runMatchup},
+	{name: "points", usage: "score players' projected or actual stats with the league's scoring", run: runPoints},
+	{name: "players", usage: "search the league's players", run: runPlayers},
+	{name: "playermap", usage: "list, set or remove SportsData to Yahoo player mappings", run: runPlayerMap},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: hockey-hacks [-v] <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nFlags:")
+	flag.PrintDefaults()
+}
+
+// yahooClient returns an authenticated Yahoo client and the team key to act
+// on, defaulting to the first configured team.
+func yahooClient(teamKey string) (*yahoo.YahooClient, string, error) {
+	if teamKey == "" {
+		cfg, err := config.Load(config.Path())
+		if err != nil {
+			return nil, "", err
+		}
+		teamKey = cfg.Teams[0].TeamKey
+	}
+	if teamKey == "" {
+		return nil, "", errors.New("no team key")
+	}
+
+	yc := yahoo.NewYahooClient(false)
+	if err := yc.Authenticate(); err != nil {
+		return nil, "", err
+	}
+	return yc, teamKey, nil
+}
+
+func main() {
+	verbose := flag.Bool("v", false, "Log API requests and responses to stderr")
+	flag.Usage = usage
+	flag.Parse()
+
+	godotenv.Load(config.RootDir + "/.env")
+
+	// The clients log every request and response body, which is only
+	// worth seeing when debugging
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	args := flag.Args()
+	if len(args) < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			if err := c.run(args[1:]); err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	usage()
+	os.Exit(2)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hockey-hacks/pkg/yahoo"
+	"os"
+	"text/tabwriter"
+)
+
+func runMatchup(args []string) error {
+	fs := flag.NewFlagSet("matchup", flag.ExitOnError)
+	team := fs.String("team", "", "Yahoo team key (default: first configured team)")
+	week := fs.Int("week", 0, "Fantasy week (default: current week)")
+	fs.Parse(args)
+
+	yc, teamKey, err := yahooClient(*team)
+	if err != nil {
+		return err
+	}
+	league, err := yc.GetLeague(yahoo.LeagueKey(teamKey))
+	if err != nil {
+		return err
+	}
+	if *week == 0 {
+		*week = league.CurrentWeek
+	}
+	m, err := yc.GetMatchup(teamKey, *week)
+	if err != nil {
+		return err
+	}
+	us, them, err := m.Sides(teamKey)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Week %d (%s to %s): %s vs %s, %s\n", m.Week, m.WeekStart, m.WeekEnd, us.Name, them.Name, m.Status)
+	fmt.Printf("Score: %g - %g", us.TeamPoints.Total, them.TeamPoints.Total)
+	if us.TeamProjectedPoints.Total != 0 || them.TeamProjectedPoints.Total != 0 {
+		fmt.Printf(" (projected %.2f - %.2f)", us.TeamProjectedPoints.Total, them.TeamProjectedPoints.Total)
+	}
+	fmt.Println()
+
+	scores, err := m.Categories(teamKey, league.Settings)
+	if err != nil {
+		return err
+	}
+	if len(scores) == 0 {
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "CATEGORY\tUS\tTHEM\tLEADER\n")
+	for _, s := range scores {
+		leader := "tied"
+		switch s.Leader {
+		case us.TeamKey:
+			leader = us.Name
+		case them.TeamKey:
+			leader = them.Name
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Category.DisplayName, s.Ours, s.Theirs, leader)
+	}
+	return w.Flush()
+}
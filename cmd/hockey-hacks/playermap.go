@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"hockey-hacks/pkg/playerMap"
+	"os"
+	"text/tabwriter"
+)
+
+func runPlayerMap(args []string) error {
+	if len(args) < 1 {
+		return errors.New("usage: hockey-hacks playermap <list|set|remove> [flags]")
+	}
+
+	fs := flag.NewFlagSet("playermap "+args[0], flag.ExitOnError)
+	path := fs.String("file", playerMap.Path(), "Player map file")
+	sportsDataID := fs.Int("sportsdata", 0, "SportsData.io player ID")
+	yahooKey := fs.String("yahoo", "", "Yahoo player key, e.g. 465.p.7713")
+	name := fs.String("name", "", "Player name")
+	team := fs.String("team", "", "NHL team abbreviation")
+	fs.Parse(args[1:])
+
+	ids, err := playerMap.Load(*path)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SPORTSDATA\tYAHOO\tNAME\tTEAM\tSOURCE")
+		for _, e := range ids.Entries() {
+			source := "auto"
+			if e.Manual {
+				source = "manual"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", e.SportsDataID, e.YahooKey, e.Name, e.Team, source)
+		}
+		return w.Flush()
+	case "set":
+		if *sportsDataID == 0 || *yahooKey == "" {
+			return errors.New("set requires -sportsdata and -yahoo")
+		}
+		ids.Set(playerMap.Entry{
+			SportsDataID: *sportsDataID,
+			YahooKey:     *yahooKey,
+			Name:         *name,
+			Team:         *team,
+			Manual:       true,
+		})
+	case "remove":
+		if *sportsDataID == 0 {
+			return errors.New("remove requires -sportsdata")
+		}
+		if !ids.Remove(*sportsDataID) {
+			return fmt.Errorf("no mapping for SportsData player %d", *sportsDataID)
+		}
+	default:
+		return fmt.Errorf("unknown playermap command %q", args[0])
+	}
+
+	return ids.Save()
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"hockey-hacks/pkg/yahoo"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// playerRow is a search result as printed by players search.
+type playerRow struct {
+	PlayerKey    string            `json:"player_key"`
+	Name         string            `json:"name"`
+	Team         string            `json:"team"`
+	Positions    []string          `json:"positions"`
+	PercentOwned float64           `json:"percent_owned"`
+	Points       float64           `json:"points,omitempty"`
+	Stats        map[string]string `json:"stats,omitempty"`
+}
+
+func newPlayerRow(p yahoo.Player) playerRow {
+	row := playerRow{
+		PlayerKey:    p.PlayerKey,
+		Name:         p.Name.Full,
+		Team:         p.EditorialTeamAbbr,
+		Positions:    p.EligiblePositions.Positions,
+		PercentOwned: p.PercentOwned.Value,
+		Points:       p.PlayerPoints.Total,
+	}
+	for _, s := range p.PlayerStats.Stats.Stat {
+		if row.Stats == nil {
+			row.Stats = make(map[string]string)
+		}
+		row.Stats[fmt.Sprint(s.StatID)] = s.Value
+	}
+	return row
+}
+
+// statIDs returns the IDs of the row's stats in numeric order.
+func (r playerRow) statIDs() []string {
+	var ids []string
+	for id := range r.Stats {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+	return ids
+}
+
+func runPlayers(args []string) error {
+	if len(args) < 1 || args[0] != "search" {
+		return errors.New("usage: hockey-hacks players search [flags]")
+	}
+
+	fs := flag.NewFlagSet("players search", flag.ExitOnError)
+	team := fs.String("team", "", "Yahoo team key whose league to search (default: first configured team)")
+	status := fs.String("status", yahoo.StatusFreeAgent, "Player status: FA, W, T, A or K")
+	position := fs.String("position", "", "Position, e.g. G or C")
+	search := fs.String("search", "", "Player name to search for")
+	sortBy := fs.String("sort", yahoo.SortActualRank, "Sort by AR, OR, PTS, NAME, OWN or a stat ID; OWN only reorders the players fetched by AR")
+	statsType := fs.String("stats", "", "Stats period to sort by and show: season, lastweek or lastmonth")
+	start := fs.Int("start", 0, "Index of the first player")
+	count := fs.Int("count", 25, "Number of players, 0 for all")
+	asJSON := fs.Bool("json", false, "Print the players as JSON")
+	fs.Parse(args[1:])
+
+	yc, teamKey, err := yahooClient(*team)
+	if err != nil {
+		return err
+	}
+	players, err := yc.SearchPlayers(yahoo.LeagueKey(teamKey), yahoo.PlayerSearch{
+		Status:    *status,
+		Position:  *position,
+		Search:    *search,
+		Sort:      *sortBy,
+		StatsType: *statsType,
+		Start:     *start,
+		Count:     *count,
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := []playerRow{}
+	for _, p := range players.PlayerList {
+		rows = append(rows, newPlayerRow(p))
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tNAME\tTEAM\tPOS\tOWNED\tSTATS")
+	for _, r := range rows {
+		var stats []string
+		for _, id := range r.statIDs() {
+			stats = append(stats, id+":"+r.Stats[id])
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.0f%%\t%s\n", r.PlayerKey, r.Name, r.Team, strings.Join(r.Positions, ","), r.PercentOwned, strings.Join(stats, " "))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hockey-hacks/pkg/clock"
+	"hockey-hacks/pkg/scoring"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+func runPoints(args []string) error {
+	fs := flag.NewFlagSet("points", flag.ExitOnError)
+	team := fs.String("team", "", "Yahoo team key whose league scoring to use (default: first configured team)")
+	player := fs.String("player", "", "SportsData.io player ID or part of a name (default: rank every player)")
+	dateFlag := fs.String("date", "", "Game date, as YYYY-MM-DD (default today)")
+	actual := fs.Bool("actual", false, "Score the actual stats instead of the projections")
+	count := fs.Int("count", 25, "Number of players to rank, 0 for all")
+	asJSON := fs.Bool("json", false, "Print the scores as JSON")
+	fs.Parse(args)
+
+	date := clock.Today(clock.Real{})
+	if *dateFlag != "" {
+		var err error
+		if date, err = clock.ParseDate(clock.Real{}, *dateFlag); err != nil {
+			return err
+		}
+	}
+
+	yc, teamKey, err := yahooClient(*team)
+	if err != nil {
+		return err
+	}
+	league, err := yc.GetLeague(yahoo.LeagueKey(teamKey))
+	if err != nil {
+		return err
+	}
+
+	sd := sportsData.NewClient(false)
+	var lines map[int]sportsData.Player
+	if *actual {
+		lines, err = sd.GetPlayerGameStats(date)
+	} else {
+		lines, err = sd.GetPlayerProjections(date)
+	}
+	if err != nil {
+		return err
+	}
+	if *player != "" {
+		lines = findPlayers(lines, *player)
+		if len(lines) == 0 {
+			return fmt.Errorf("no stat line for %q on %s", *player, date.Format("2006-01-02"))
+		}
+	}
+
+	ranked, err := scoring.Rank(league.Settings, lines)
+	if err != nil {
+		return err
+	}
+	if *count > 0 && len(ranked) > *count {
+		ranked = ranked[:*count]
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ranked)
+	}
+	if *player != "" {
+		for i, r := range ranked {
+			if i > 0 {
+				fmt.Println()
+			}
+			if err := writeScore(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tTEAM\tPOS\tOPP\tLEAGUE PTS\tYAHOO DEFAULT PTS")
+	for _, r := range ranked {
+		p := r.Player
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%.2f\t%.2f\n", p.PlayerID, p.Name, p.Team, p.Position, p.Opponent, r.Score.Points, p.FantasyPointsYahoo)
+	}
+	return w.Flush()
+}
+
+// findPlayers returns the stat lines of the player with the ID, or of every
+// player whose name contains the search.
+func findPlayers(lines map[int]sportsData.Player, search string) map[int]sportsData.Player {
+	found := make(map[int]sportsData.Player)
+	if id, err := strconv.Atoi(search); err == nil {
+		if p, ok := lines[id]; ok {
+			found[id] = p
+		}
+		return found
+	}
+	search = strings.ToLower(search)
+	for id, p := range lines {
+		if strings.Contains(strings.ToLower(p.Name), search) {
+			found[id] = p
+		}
+	}
+	return found
+}
+
+// writeScore prints how a player's points add up.
+func writeScore(r scoring.Ranked) error {
+	p := r.Player
+	fmt.Printf("%s (%s, %s) vs %s: %.2f league points, %.2f with Yahoo's default scoring\n", p.Name, p.Team, p.Position, p.Opponent, r.Score.Points, p.FantasyPointsYahoo)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAT\tVALUE\tPTS EACH\tPOINTS")
+	for _, l := range r.Score.Lines {
+		fmt.Fprintf(w, "%s\t%.2f\t%g\t%.2f\n", l.Name, l.Value, l.Modifier, l.Points)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(r.Score.Unsupported) > 0 {
+		fmt.Printf("Not in SportsData.io stat lines, counted as 0: stat IDs %v\n", r.Score.Unsupported)
+	}
+	return nil
+}
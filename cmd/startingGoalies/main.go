@@ -2,26 +2,104 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"hockey-hacks/pkg/clock"
+	"hockey-hacks/pkg/config"
 	"hockey-hacks/pkg/goalies"
+	"hockey-hacks/pkg/lineup"
+	"hockey-hacks/pkg/playerMap"
 	"hockey-hacks/pkg/sportsData"
 	"hockey-hacks/pkg/yahoo"
 	"log"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type result struct {
-	goalies sportsData.Goalies
-	err     error
+	games sportsData.Games
+	err   error
+}
+
+// gameCache shares SportsData fetches between teams so each date is only
+// requested once per run.
+type gameCache struct {
+	sd          *sportsData.Client
+	mu          sync.Mutex
+	games       map[string]sportsData.Games
+	schedules   map[string]sportsData.Games
+	projections map[string]sportsData.Projections
+}
+
+func (gc *gameCache) get(date time.Time) (sportsData.Games, error) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	day := date.Format(time.DateOnly)
+	if games, ok := gc.games[day]; ok {
+		return games, nil
+	}
+	games, err := gc.sd.GetStartingGoalies(date)
+	if err != nil {
+		return nil, err
+	}
+	gc.games[day] = games
+	return games, nil
+}
+
+// schedule returns every game on the date, whether or not its starting
+// goalies are projected yet.
+func (gc *gameCache) schedule(date time.Time) (sportsData.Games, error) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	day := date.Format(time.DateOnly)
+	if games, ok := gc.schedules[day]; ok {
+		return games, nil
+	}
+	games, err := gc.sd.GetGames(date)
+	if err != nil {
+		return nil, err
+	}
+	gc.schedules[day] = games
+	return games, nil
+}
+
+// playerProjections returns every player's projected stats for the date.
+func (gc *gameCache) playerProjections(date time.Time) (sportsData.Projections, error) {
+	gc.mu.Lock()
+	defer gc.mu.Unlock()
+
+	day := date.Format(time.DateOnly)
+	if projections, ok := gc.projections[day]; ok {
+		return projections, nil
+	}
+	projections, err := gc.sd.GetPlayerProjections(date)
+	if err != nil {
+		return nil, err
+	}
+	gc.projections[day] = projections
+	return projections, nil
+}
+
+type options struct {
+	date    time.Time
+	skaters bool
+	dryRun  bool
 }
 
 func main() {
 	enableEmail := flag.Bool("email", false, "Enable email notifications on failures")
+	enableSkaters := flag.Bool("skaters", false, "Also optimize the skater lineup for every team")
+	dryRun := flag.Bool("dry-run", false, "Print the lineup plan without changing the roster")
+	planJSON := flag.Bool("json", false, "Print the lineup plan as JSON")
+	configPath := flag.String("config", "", "Team config file (default $CONFIG_PATH or ../../config.json)")
+	dateFlag := flag.String("date", "", "Date to set the lineup for, as YYYY-MM-DD (default today)")
 	flag.Parse()
 
-	godotenv.Load("../../.env")
+	godotenv.Load(config.RootDir + "/.env")
 
 	f, err := os.OpenFile("./logs.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
@@ -33,37 +111,356 @@ func main() {
 
 	log.Println("Starting Program")
 	log.Printf("Email notifications: %t", *enableEmail)
+	log.Printf("Skater lineup: %t", *enableSkaters)
+	log.Printf("Dry run: %t", *dryRun)
+
+	clk := clock.Real{}
+	date := clock.Today(clk)
+	if *dateFlag != "" {
+		date, err = clock.ParseDate(clk, *dateFlag)
+		if err != nil {
+			log.Fatalln("Invalid -date:", err)
+		}
+	}
+	log.Printf("Lineup date: %s", date.Format(time.DateOnly))
+
+	if *configPath == "" {
+		*configPath = config.Path()
+	}
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalln("Failed to load config:", err)
+	}
+	for _, tc := range cfg.Teams {
+		if _, err := yahoo.ParseStarterPolicy(tc.StarterPolicy); err != nil {
+			log.Fatalf("Invalid config for team %s: %v", tc.TeamKey, err)
+		}
+	}
 
-	yahoo := yahoo.NewYahooClient(*enableEmail)
+	yc := yahoo.NewYahooClient(*enableEmail)
+	sd := sportsData.NewClient(*enableEmail)
 
 	var wg sync.WaitGroup
 
 	resultChan := make(chan result, 1)
 
+	var authErr error
 	wg.Add(2)
-	go yahoo.RefreshAuth(&wg)
 	go func() {
 		defer wg.Done()
-		games, err := sportsData.GetStartingGoalies()
+		authErr = yc.Authenticate()
+	}()
+	go func() {
+		defer wg.Done()
+		games, err := sd.GetStartingGoalies(date)
 		if err != nil {
 			resultChan <- result{err: err}
 			return
 		}
-		startingGoalies := goalies.GetTeamStartingGoalies(games)
-		resultChan <- result{goalies: startingGoalies, err: nil}
+		resultChan <- result{games: games, err: nil}
 	}()
 	wg.Wait()
 
 	res := <-resultChan
 	if res.err != nil {
-		os.Exit(1)
+		log.Fatalln("Failed to get starting goalies:", res.err)
+	}
+	if authErr != nil {
+		log.Fatalln("Yahoo auth failed:", authErr)
+	}
+
+	ids, err := playerMap.Load(playerMap.Path())
+	if err != nil {
+		log.Fatalf("Failed to load player map: %v", err)
 	}
-	if len(res.goalies) == 0 {
-		log.Println("No starting goalies found.")
-		os.Exit(0)
+
+	cache := &gameCache{
+		sd:          sd,
+		games:       map[string]sportsData.Games{date.Format(time.DateOnly): res.games},
+		schedules:   make(map[string]sportsData.Games),
+		projections: make(map[string]sportsData.Projections),
+	}
+	opts := options{date: date, skaters: *enableSkaters, dryRun: *dryRun}
+	plans := make(yahoo.Plans, len(cfg.Teams))
+	for i, tc := range cfg.Teams {
+		wg.Add(1)
+		go func(i int, tc config.TeamConfig) {
+			defer wg.Done()
+			plans[i] = runTeam(yc, tc, cache, ids, opts)
+		}(i, tc)
+	}
+	wg.Wait()
+
+	// A dry run leaves the player map as it found it; new matches are made
+	// again on the next run.
+	if *dryRun {
+		log.Println("Dry run, not saving the player map")
+	} else if err := ids.Save(); err != nil {
+		log.Printf("Failed to save player map: %v", err)
+	}
+
+	if *planJSON {
+		plans.WriteJSON(os.Stdout)
+	} else if *dryRun {
+		plans.WriteText(os.Stdout)
 	}
 
-	yahoo.SwapPlayers(res.goalies)
+	failed := 0
+	for _, plan := range plans {
+		if plan.Error != "" {
+			failed++
+			log.Printf("%s failed: %s", plan.TeamKey, plan.Error)
+		} else {
+			log.Printf("%s: %d lineup moves", plan.TeamKey, len(plan.Moves))
+		}
+	}
 
 	log.Printf("Ending Program\n")
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runTeam sets the lineup for a single team. Failures are reported in the
+// returned plan so that one team does not stop the others.
+func runTeam(yc *yahoo.YahooClient, tc config.TeamConfig, cache *gameCache, ids *playerMap.Map, opts options) yahoo.Plan {
+	plan := yahoo.Plan{TeamKey: tc.TeamKey, Date: opts.date.Format(time.DateOnly)}
+
+	league, err := yc.GetLeague(yahoo.LeagueKey(tc.TeamKey))
+	if err != nil {
+		plan.Error = err.Error()
+		return plan
+	}
+
+	var coverage yahoo.Coverage
+	var moves []yahoo.Move
+	if league.LineupCoverage() == yahoo.CoverageWeek {
+		coverage, moves, err = planWeek(yc, tc, league, cache, ids, opts, &plan)
+	} else {
+		coverage, moves, err = planDay(yc, tc, league, cache, ids, opts, &plan)
+	}
+	if err != nil {
+		plan.Error = err.Error()
+		return plan
+	}
+	plan.Week = coverage.Week
+
+	plan.Moves = yahoo.Changes(yahoo.Merge(moves))
+	if opts.dryRun {
+		log.Printf("%s: Dry run, not sending: %v", tc.TeamKey, yahoo.NewSwapPlayerRequest(coverage, plan.Moves))
+		return plan
+	}
+
+	if _, err := yc.SetLineup(tc.TeamKey, coverage, plan.Moves); err != nil {
+		plan.Error = err.Error()
+	}
+	return plan
+}
+
+// planDay plans the lineup of a daily league for the run's date, streaming
+// a free-agent goalie into the plan first when the team is set up for it.
+func planDay(yc *yahoo.YahooClient, tc config.TeamConfig, league yahoo.League, cache *gameCache, ids *playerMap.Map, opts options, plan *yahoo.Plan) (yahoo.Coverage, []yahoo.Move, error) {
+	coverage := yahoo.DateCoverage(opts.date)
+
+	games, err := cache.get(opts.date)
+	if err != nil {
+		return coverage, nil, err
+	}
+	schedule, err := cache.schedule(opts.date)
+	if err != nil {
+		return coverage, nil, err
+	}
+	team, err := yc.GetTeamRoster(tc.TeamKey, coverage)
+	if err != nil {
+		return coverage, nil, err
+	}
+
+	if tc.StreamGoalies && !tc.SkipGoalies {
+		pickup, err := goalies.Stream(yc, team, games, ids, goalies.StreamOptions{
+			Budget:      yahoo.NewAddBudget(league, team),
+			Untouchable: tc.UntouchableGoalies,
+			DryRun:      opts.dryRun,
+		})
+		if err != nil {
+			return coverage, nil, err
+		}
+		if pickup != nil {
+			plan.Pickups = append(plan.Pickups, *pickup)
+			if !opts.dryRun {
+				// Start the new goalie
+				if team, err = yc.GetTeamRoster(tc.TeamKey, coverage); err != nil {
+					return coverage, nil, err
+				}
+			}
+		}
+	}
+	roster := team.Roster.Players
+	locked := yahoo.Locked(roster, schedule, league.Settings, yc.Clock.Now())
+	if len(locked) > 0 {
+		warning := fmt.Sprintf("%d players are locked and keep their slots", len(locked))
+		log.Printf("%s: %s", tc.TeamKey, warning)
+		plan.Warnings = append(plan.Warnings, warning)
+	}
+
+	var moves []yahoo.Move
+	if tc.InjuredReserve {
+		moves = planInjuries(tc, league, roster, locked, plan)
+		roster = roster.WithMoves(moves)
+	}
+	var projected yahoo.Projected
+	if tc.Projections {
+		projections, err := cache.playerProjections(opts.date)
+		if err != nil {
+			return coverage, nil, err
+		}
+		projected = yahoo.RosterProjections(roster, projections, ids)
+		log.Printf("%s: %d rostered players have projections", tc.TeamKey, len(projected))
+	}
+	if !tc.SkipGoalies {
+		startingGoalies := goalies.GetTeamStartingGoalies(games, roster.Goalies(), ids)
+		if len(startingGoalies) == 0 {
+			log.Printf("%s: No starting goalies found.", tc.TeamKey)
+		} else {
+			policy, err := yahoo.ParseStarterPolicy(tc.StarterPolicy)
+			if err != nil {
+				return coverage, nil, err
+			}
+			goalieMoves := yahoo.PlanGoalies(roster, startingGoalies, ids, league.Settings, policy, projected, locked)
+			projected := 0
+			for _, m := range goalieMoves {
+				if m.Basis == yahoo.BasisProjected {
+					projected++
+				}
+			}
+			if projected > 0 {
+				log.Printf("%s: %d goalie decisions rest on unconfirmed projections (%s policy), the next run re-checks them", tc.TeamKey, projected, policy)
+			}
+			if tc.RatioProtection.Enabled {
+				if goalieMoves, err = protectRatios(yc, tc, league, opts.date, goalieMoves, locked); err != nil {
+					return coverage, nil, err
+				}
+			}
+			moves = append(moves, goalieMoves...)
+		}
+	}
+
+	if tc.Skaters || opts.skaters {
+		moves = append(moves, lineup.OptimizeProjected(roster, schedule.Teams(), lineup.Slots(league.Settings), projected, locked)...)
+	}
+
+	return coverage, moves, nil
+}
+
+// planWeek plans the lineup of a weekly league for the fantasy week that
+// contains the run's date, or the week after once the weekly deadline has
+// locked it, counting each goalie's team games from the schedule and the
+// goalie's projected starts where they are known.
+func planWeek(yc *yahoo.YahooClient, tc config.TeamConfig, league yahoo.League, cache *gameCache, ids *playerMap.Map, opts options, plan *yahoo.Plan) (yahoo.Coverage, []yahoo.Move, error) {
+	weeks, err := yc.GetGameWeeks(yahoo.GameKey(tc.TeamKey))
+	if err != nil {
+		return yahoo.Coverage{}, nil, err
+	}
+	week, err := yahoo.WeekOf(weeks, opts.date)
+	if err != nil {
+		return yahoo.Coverage{}, nil, err
+	}
+	coverage := yahoo.WeekCoverage(week.Week)
+
+	team, err := yc.GetTeamRoster(tc.TeamKey, coverage)
+	if err != nil {
+		return coverage, nil, err
+	}
+	if team.Roster.IsEditable == 0 {
+		next, ok := yahoo.NextWeek(weeks, week)
+		if !ok {
+			warning := fmt.Sprintf("week %d lineup is locked and it is the last week", week.Week)
+			log.Printf("%s: %s", tc.TeamKey, warning)
+			plan.Warnings = append(plan.Warnings, warning)
+			return coverage, nil, nil
+		}
+		log.Printf("%s: Week %d lineup is locked, planning week %d", tc.TeamKey, week.Week, next.Week)
+		week, coverage = next, yahoo.WeekCoverage(next.Week)
+		if team, err = yc.GetTeamRoster(tc.TeamKey, coverage); err != nil {
+			return coverage, nil, err
+		}
+		if team.Roster.IsEditable == 0 {
+			warning := fmt.Sprintf("week %d and %d lineups are locked", week.Week-1, week.Week)
+			log.Printf("%s: %s", tc.TeamKey, warning)
+			plan.Warnings = append(plan.Warnings, warning)
+			return coverage, nil, nil
+		}
+	}
+	roster := team.Roster.Players
+
+	var moves []yahoo.Move
+	if tc.InjuredReserve {
+		moves = planInjuries(tc, league, roster, nil, plan)
+		roster = roster.WithMoves(moves)
+	}
+
+	if tc.Skaters || opts.skaters {
+		log.Printf("%s: Skater lineup is only optimized for daily leagues", tc.TeamKey)
+	}
+	if tc.SkipGoalies {
+		return coverage, moves, nil
+	}
+
+	dates, err := week.Dates(opts.date.Location())
+	if err != nil {
+		return coverage, nil, err
+	}
+	var schedules []sportsData.Games
+	var days []sportsData.Goalies
+	for _, date := range dates {
+		schedule, err := cache.schedule(date)
+		if err != nil {
+			return coverage, nil, err
+		}
+		schedules = append(schedules, schedule)
+		games, err := cache.get(date)
+		if err != nil {
+			return coverage, nil, err
+		}
+		days = append(days, goalies.GetTeamStartingGoalies(games, roster.Goalies(), ids))
+	}
+	return coverage, append(moves, yahoo.PlanWeeklyGoalies(roster, schedules, days, ids, league.Settings)...), nil
+}
+
+// planInjuries moves players in and out of IR slots, reporting the moves it
+// had to refuse in the plan.
+func planInjuries(tc config.TeamConfig, league yahoo.League, roster yahoo.Players, locked map[string]bool, plan *yahoo.Plan) []yahoo.Move {
+	moves, warnings := yahoo.PlanInjuries(roster, league.Settings, locked)
+	for _, warning := range warnings {
+		log.Printf("%s: %s", tc.TeamKey, warning)
+	}
+	plan.Warnings = append(plan.Warnings, warnings...)
+	return moves
+}
+
+// protectRatios benches the goalies the moves would start when a bad start
+// would cost the team its GAA or SV% lead in the matchup of the week
+// containing date.
+func protectRatios(yc *yahoo.YahooClient, tc config.TeamConfig, league yahoo.League, date time.Time, moves []yahoo.Move, locked map[string]bool) ([]yahoo.Move, error) {
+	weeks, err := yc.GetGameWeeks(yahoo.GameKey(tc.TeamKey))
+	if err != nil {
+		return nil, err
+	}
+	week, err := yahoo.WeekOf(weeks, date)
+	if err != nil {
+		return nil, err
+	}
+	m, err := yc.GetMatchup(tc.TeamKey, week.Week)
+	if err != nil {
+		return nil, err
+	}
+	decision, err := yahoo.CheckRatios(m, tc.TeamKey, league.Settings, yahoo.RatioRisk{
+		BadStartGoalsAgainst: tc.RatioProtection.BadStartGoalsAgainst,
+		BadStartShots:        tc.RatioProtection.BadStartShots,
+		CategoriesAtRisk:     tc.RatioProtection.CategoriesAtRisk,
+		MinGames:             tc.RatioProtection.MinGames,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return yahoo.ProtectRatios(moves, tc.TeamKey, decision, locked), nil
 }
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/xml"
+	"hockey-hacks/pkg/clock"
+	"hockey-hacks/pkg/config"
+	"hockey-hacks/pkg/fakeServer"
+	"hockey-hacks/pkg/playerMap"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const teamKey = "465.l.1234.t.8"
+
+// lineupDate is the date of the roster and schedule fixtures.
+var lineupDate = time.Date(2024, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// newRun returns the clients and cache for a run against the fake server,
+// in the morning before any game locks.
+func newRun(t *testing.T, s *fakeServer.Server) (*yahoo.YahooClient, *gameCache, *playerMap.Map) {
+	t.Helper()
+	yc := s.YahooClient()
+	yc.Clock = clock.Fixed(lineupDate.Add(14 * time.Hour))
+	cache := &gameCache{
+		sd:          s.SportsDataClient(),
+		games:       make(map[string]sportsData.Games),
+		schedules:   make(map[string]sportsData.Games),
+		projections: make(map[string]sportsData.Projections),
+	}
+	ids, err := playerMap.Load(filepath.Join(t.TempDir(), "player_map.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return yc, cache, ids
+}
+
+// lineupPut decodes the single roster PUT the run sent.
+func lineupPut(t *testing.T, s *fakeServer.Server) yahoo.SwapPlayerRequest {
+	t.Helper()
+	puts := s.RequestsTo(http.MethodPut, "/team/"+teamKey+"/roster")
+	if len(puts) != 1 {
+		t.Fatalf("got %d roster PUTs, want 1", len(puts))
+	}
+	var req yahoo.SwapPlayerRequest
+	if err := xml.Unmarshal(puts[0].Body, &req); err != nil {
+		t.Fatalf("decoding PUT body: %v\n%s", err, puts[0].Body)
+	}
+	return req
+}
+
+func TestRunTeamSetsGoalies(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	yc, cache, ids := newRun(t, s)
+
+	plan := runTeam(yc, config.TeamConfig{TeamKey: teamKey}, cache, ids, options{date: lineupDate})
+	if plan.Error != "" {
+		t.Fatalf("run failed: %s", plan.Error)
+	}
+
+	tokens := s.RequestsTo(http.MethodPost, "/oauth2/get_token")
+	if len(tokens) != 1 {
+		t.Fatalf("got %d token requests, want 1", len(tokens))
+	}
+	form, err := url.ParseQuery(string(tokens[0].Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("grant_type") != "refresh_token" {
+		t.Errorf("grant_type = %q, want refresh_token", form.Get("grant_type"))
+	}
+
+	rosterPath := "/team/" + teamKey + "/roster;date=2024-10-19/players"
+	gets := s.RequestsTo(http.MethodGet, rosterPath)
+	if len(gets) != 1 {
+		t.Fatalf("got %d GETs of %s, want 1", len(gets), rosterPath)
+	}
+	if auth := gets[0].Header.Get("Authorization"); auth != "Bearer fake-access-token" {
+		t.Errorf("roster GET Authorization = %q", auth)
+	}
+
+	req := lineupPut(t, s)
+	if req.Roster.CoverageType != yahoo.CoverageDate || req.Roster.Date != "2024-10-19" {
+		t.Errorf("PUT coverage = %s %s, want date 2024-10-19", req.Roster.CoverageType, req.Roster.Date)
+	}
+	// Woll is the confirmed TOR starter and Swayman the projected BOS one;
+	// their teammates go to the bench.
+	want := map[string]string{
+		"465.p.8640": yahoo.PositionGoalie, // Woll
+		"465.p.7191": yahoo.PositionGoalie, // Swayman
+		"465.p.5734": yahoo.PositionBench,  // Stolarz
+		"465.p.4718": yahoo.PositionBench,  // Korpisalo
+	}
+	got := make(map[string]string)
+	for _, p := range req.Roster.Players.Player {
+		got[p.PlayerKey] = p.Position
+	}
+	for key, pos := range want {
+		if got[key] != pos {
+			t.Errorf("%s moved to %q, want %q", key, got[key], pos)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("PUT moved %d players, want %d: %v", len(got), len(want), got)
+	}
+}
+
+func TestRunTeamRefreshesExpiredToken(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	s.HandleN(http.MethodGet, "/roster", fakeServer.Response{
+		Status: http.StatusUnauthorized,
+		Body:   fakeServer.Fixture("error_auth_expired.xml"),
+	}, 1)
+	yc, cache, ids := newRun(t, s)
+
+	plan := runTeam(yc, config.TeamConfig{TeamKey: teamKey}, cache, ids, options{date: lineupDate})
+	if plan.Error != "" {
+		t.Fatalf("run failed: %s", plan.Error)
+	}
+
+	if n := len(s.RequestsTo(http.MethodPost, "/oauth2/get_token")); n != 2 {
+		t.Errorf("got %d token requests, want 2: the first token and the refresh after the 401", n)
+	}
+	if n := len(s.RequestsTo(http.MethodGet, "/roster;date=2024-10-19")); n != 2 {
+		t.Errorf("got %d roster GETs, want 2: the rejected one and the retry", n)
+	}
+	lineupPut(t, s)
+}
+
+func TestRunTeamDryRunSendsNothing(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	yc, cache, ids := newRun(t, s)
+
+	plan := runTeam(yc, config.TeamConfig{TeamKey: teamKey}, cache, ids, options{date: lineupDate, dryRun: true})
+	if plan.Error != "" {
+		t.Fatalf("run failed: %s", plan.Error)
+	}
+	if len(plan.Moves) == 0 {
+		t.Error("dry run planned no moves")
+	}
+	for _, r := range s.Requests() {
+		if r.Method == http.MethodPut || (r.Method == http.MethodPost && !strings.Contains(r.Path, "get_token")) {
+			t.Errorf("dry run sent %s %s", r.Method, r.Path)
+		}
+	}
+}
+
+func TestRunTeamKeepsLockedPlayers(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	yc, cache, ids := newRun(t, s)
+	// The league locks daily and the day's first games started at 23:00.
+	yc.Clock = clock.Fixed(lineupDate.Add(23*time.Hour + 30*time.Minute))
+
+	plan := runTeam(yc, config.TeamConfig{TeamKey: teamKey}, cache, ids, options{date: lineupDate})
+	if plan.Error != "" {
+		t.Fatalf("run failed: %s", plan.Error)
+	}
+	for _, m := range plan.Moves {
+		if m.Position != m.Current {
+			t.Errorf("moved locked %s from %s to %s", m.Name, m.Current, m.Position)
+		}
+	}
+	if n := len(s.RequestsTo(http.MethodPut, "/roster")); n != 0 {
+		t.Errorf("got %d roster PUTs, want none", n)
+	}
+}
+
+// weeklyLeague makes the fake league lock lineups once a week.
+func weeklyLeague(s *fakeServer.Server) {
+	settings := strings.Replace(string(fakeServer.Fixture("league_settings.xml")),
+		"<settings>", "<settings>\n   <weekly_deadline>1</weekly_deadline>", 1)
+	s.Handle(http.MethodGet, "/settings", fakeServer.Response{Body: []byte(settings)})
+}
+
+// lockedRoster is the roster fixture past the weekly deadline.
+func lockedRoster() fakeServer.Response {
+	roster := strings.Replace(string(fakeServer.Fixture("roster.xml")),
+		"<is_editable>1</is_editable>", "<is_editable>0</is_editable>", 1)
+	return fakeServer.Response{Body: []byte(roster)}
+}
+
+func TestRunTeamPlansNextWeekPastDeadline(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	weeklyLeague(s)
+	s.Handle(http.MethodGet, "/roster;week=2/", lockedRoster())
+	yc, cache, ids := newRun(t, s)
+
+	plan := runTeam(yc, config.TeamConfig{TeamKey: teamKey}, cache, ids, options{date: lineupDate})
+	if plan.Error != "" {
+		t.Fatalf("run failed: %s", plan.Error)
+	}
+	if plan.Week != 3 {
+		t.Errorf("planned week %d, want 3", plan.Week)
+	}
+	req := lineupPut(t, s)
+	if req.Roster.CoverageType != yahoo.CoverageWeek || req.Roster.Week != 3 {
+		t.Errorf("PUT coverage = %s %d, want week 3", req.Roster.CoverageType, req.Roster.Week)
+	}
+}
+
+func TestRunTeamSkipsLockedWeeks(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	weeklyLeague(s)
+	s.Handle(http.MethodGet, "/roster;week=", lockedRoster())
+	yc, cache, ids := newRun(t, s)
+
+	plan := runTeam(yc, config.TeamConfig{TeamKey: teamKey}, cache, ids, options{date: lineupDate})
+	if plan.Error != "" {
+		t.Fatalf("run failed on locked weeks: %s", plan.Error)
+	}
+	if len(plan.Warnings) == 0 {
+		t.Error("no warning about the locked weeks")
+	}
+	if n := len(s.RequestsTo(http.MethodPut, "/roster")); n != 0 {
+		t.Errorf("got %d roster PUTs into locked weeks, want none", n)
+	}
+}
+
+func TestRunTeamProtectsRatiosInTheRunsWeek(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	matchup := strings.ReplaceAll(string(fakeServer.Fixture("matchups.xml")), "<week>2</week>", "<week>3</week>")
+	s.Handle(http.MethodGet, "/matchups;weeks=3", fakeServer.Response{Body: []byte(matchup)})
+	yc, cache, ids := newRun(t, s)
+	// The league's current week is 2; planning a day of week 3 must check
+	// the week 3 matchup.
+	date := time.Date(2024, 10, 22, 0, 0, 0, 0, time.UTC)
+	yc.Clock = clock.Fixed(date.Add(14 * time.Hour))
+	tc := config.TeamConfig{TeamKey: teamKey}
+	tc.RatioProtection.Enabled = true
+
+	plan := runTeam(yc, tc, cache, ids, options{date: date})
+	if plan.Error != "" {
+		t.Fatalf("run failed: %s", plan.Error)
+	}
+	if n := len(s.RequestsTo(http.MethodGet, "/matchups;weeks=3")); n != 1 {
+		t.Errorf("got %d GETs of the week 3 matchup, want 1", n)
+	}
+	if n := len(s.RequestsTo(http.MethodGet, "/matchups;weeks=2")); n != 0 {
+		t.Errorf("got %d GETs of the current week's matchup, want none", n)
+	}
+}
@@ -0,0 +1,142 @@
+// Package audit keeps an append-only local log of every change made to a
+// roster, whether by this tool or by the league, as JSON lines.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"hockey-hacks/pkg/config"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPath = config.RootDir + "/audit.log"
+
+	// Entry sources
+	SourceClient = "client"
+	SourceLeague = "league"
+)
+
+// Path returns the audit log location from AUDIT_LOG_PATH or the default.
+func Path() string {
+	if path := os.Getenv("AUDIT_LOG_PATH"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// Player is a player affected by a logged change.
+type Player struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	// Detail describes what happened to the player, e.g. "BN -> G"
+	Detail string `json:"detail,omitempty"`
+}
+
+// Entry is one logged change: a request this tool sent, or a league
+// transaction.
+type Entry struct {
+	Time           time.Time `json:"time"`
+	Source         string    `json:"source"`
+	Type           string    `json:"type"`
+	TeamKey        string    `json:"team_key,omitempty"`
+	TransactionKey string    `json:"transaction_key,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	Players        []Player  `json:"players,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// Log appends entries to a file. Entries are never rewritten or removed.
+type Log struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func New(path string) *Log {
+	return &Log{Path: path}
+}
+
+// Append adds entries to the end of the log.
+func (l *Log) Append(entries ...Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// Entries returns every entry in the log, oldest first.
+func (l *Log) Entries() ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Filter selects log entries. Zero fields match everything.
+type Filter struct {
+	From time.Time
+	// To is exclusive
+	To time.Time
+	// Player matches a player key or part of a name
+	Player string
+	Type   string
+}
+
+// Match reports whether the entry passes the filter.
+func (f Filter) Match(e Entry) bool {
+	if !f.From.IsZero() && e.Time.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !e.Time.Before(f.To) {
+		return false
+	}
+	if f.Type != "" && !strings.EqualFold(e.Type, f.Type) {
+		return false
+	}
+	if f.Player == "" {
+		return true
+	}
+	name := strings.ToLower(f.Player)
+	for _, p := range e.Players {
+		if p.Key == f.Player || strings.Contains(strings.ToLower(p.Name), name) {
+			return true
+		}
+	}
+	return false
+}
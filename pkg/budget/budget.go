@@ -0,0 +1,121 @@
+// Package budget forecasts where a team's remaining weekly adds are best
+// spent.
+package budget
+
+import (
+	"fmt"
+	"hockey-hacks/pkg/lineup"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Day is the outlook for one day of the fantasy week.
+type Day struct {
+	Date time.Time
+	// Games is the number of NHL games that day
+	Games int
+	// Playing is the number of rostered players whose team plays
+	Playing int
+	// OpenSlots is the number of active lineup slots left empty that an
+	// added player could fill, so 0 on days without games
+	OpenSlots int
+}
+
+// Forecast returns the outlook for each day in the league's lineup, given
+// that day's games. A day with open lineup slots is one where an added
+// player adds starts, and more games mean more free agents to choose from.
+func Forecast(roster yahoo.Players, settings yahoo.LeagueSettings, dates []time.Time, games []sportsData.Games) []Day {
+	slots := lineup.Slots(settings)
+	goalieSlots := settings.GoalieSlots()
+	skaterSlots := 0
+	for _, s := range slots {
+		skaterSlots += s.Count
+	}
+	teams := make(map[string]string)
+	for _, p := range roster.PlayerList {
+		teams[p.PlayerKey] = p.Team()
+	}
+
+	var days []Day
+	for i, date := range dates {
+		playing := games[i].Teams()
+		day := Day{Date: date, Games: len(games[i])}
+
+		filled := 0
+		for _, m := range lineup.Optimize(roster, playing, slots) {
+			if m.Position != yahoo.PositionBench && playing[teams[m.PlayerKey]] {
+				filled++
+			}
+		}
+		goalies := 0
+		for _, p := range roster.PlayerList {
+			if p.IsInjuredReserve() || !playing[p.Team()] {
+				continue
+			}
+			day.Playing++
+			if p.IsGoalie() {
+				goalies++
+			}
+		}
+		if goalies > goalieSlots {
+			goalies = goalieSlots
+		}
+		if day.Games > 0 {
+			day.OpenSlots = skaterSlots + goalieSlots - filled - goalies
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// BestDays returns the days ranked by where an add is worth the most: the
+// most open slots first, then the most games.
+func BestDays(days []Day) []Day {
+	ranked := append([]Day(nil), days...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].OpenSlots != ranked[j].OpenSlots {
+			return ranked[i].OpenSlots > ranked[j].OpenSlots
+		}
+		return ranked[i].Games > ranked[j].Games
+	})
+	return ranked
+}
+
+// WriteReport writes the budget and the outlook for each day, followed by
+// the days the remaining adds are best spent on.
+func WriteReport(w io.Writer, teamKey string, b yahoo.AddBudget, days []Day) error {
+	fmt.Fprintf(w, "Add budget for %s: %s, %d moves this season\n", teamKey, b, b.Moves)
+	remaining := len(days)
+	if b.Limited() {
+		remaining = b.Remaining()
+		fmt.Fprintf(w, "%d adds remaining\n", remaining)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tGAMES\tPLAYING\tOPEN SLOTS")
+	for _, d := range days {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", d.Date.Format("Mon 2006-01-02"), d.Games, d.Playing, d.OpenSlots)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var best []string
+	for _, d := range BestDays(days) {
+		if len(best) == remaining || d.OpenSlots == 0 {
+			break
+		}
+		best = append(best, d.Date.Format("Mon 2006-01-02"))
+	}
+	if len(best) == 0 {
+		fmt.Fprintln(w, "No day needs an add")
+		return nil
+	}
+	fmt.Fprintf(w, "Best days to add: %s\n", strings.Join(best, ", "))
+	return nil
+}
@@ -0,0 +1,63 @@
+package budget_test
+
+import (
+	"hockey-hacks/pkg/budget"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"testing"
+	"time"
+)
+
+func rostered(key, team, slot string, positions ...string) yahoo.Player {
+	p := yahoo.Player{PlayerKey: key, EditorialTeamAbbr: team, PrimaryPosition: positions[0]}
+	p.EligiblePositions.Positions = positions
+	p.SelectedPosition.Position = slot
+	return p
+}
+
+func TestForecast(t *testing.T) {
+	var settings yahoo.LeagueSettings
+	settings.RosterPositions.RosterPosition = []yahoo.RosterPosition{
+		{Position: "C", PositionType: yahoo.PositionTypeSkater, Count: 1, IsStartingPosition: 1},
+		{Position: "LW", PositionType: yahoo.PositionTypeSkater, Count: 1, IsStartingPosition: 1},
+		{Position: yahoo.PositionGoalie, PositionType: yahoo.PositionTypeGoalie, Count: 1, IsStartingPosition: 1},
+		{Position: yahoo.PositionBench, Count: 2},
+		{Position: yahoo.PositionIR, Count: 1},
+	}
+	roster := yahoo.Players{PlayerList: []yahoo.Player{
+		rostered("center", "Tor", "C", "C"),
+		rostered("wing", "Bos", "LW", "LW"),
+		rostered("goalie", "Tor", yahoo.PositionGoalie, yahoo.PositionGoalie),
+		// Injured players do not play, whatever their team does
+		rostered("injured", "Bos", yahoo.PositionIR, "C", yahoo.PositionIR),
+	}}
+
+	monday := time.Date(2024, 10, 14, 0, 0, 0, 0, time.UTC)
+	dates := []time.Time{monday, monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 2)}
+	games := []sportsData.Games{
+		{{HomeTeam: "TOR", AwayTeam: "BOS"}},
+		{{HomeTeam: "BOS", AwayTeam: "NYR"}, {HomeTeam: "EDM", AwayTeam: "CGY"}},
+		nil,
+	}
+	want := []budget.Day{
+		{Date: dates[0], Games: 1, Playing: 3, OpenSlots: 0},
+		// The center and goalie are idle, leaving C and G open
+		{Date: dates[1], Games: 2, Playing: 1, OpenSlots: 2},
+		{Date: dates[2], Games: 0, Playing: 0, OpenSlots: 0},
+	}
+
+	days := budget.Forecast(roster, settings, dates, games)
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i, d := range days {
+		if d != want[i] {
+			t.Errorf("day %d = %+v, want %+v", i, d, want[i])
+		}
+	}
+
+	best := budget.BestDays(days)
+	if !best[0].Date.Equal(dates[1]) || !best[1].Date.Equal(dates[0]) {
+		t.Errorf("best days start with %s and %s, want %s and %s", best[0].Date, best[1].Date, dates[1], dates[0])
+	}
+}
@@ -0,0 +1,33 @@
+package clock
+
+import "time"
+
+// Clock tells the current time. Tests use Fixed to pin it.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the system clock.
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Fixed always returns the same time.
+type Fixed time.Time
+
+func (f Fixed) Now() time.Time {
+	return time.Time(f)
+}
+
+// Today returns midnight of the clock's current day.
+func Today(c Clock) time.Time {
+	now := c.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// ParseDate parses a YYYY-MM-DD date in the clock's time zone.
+func ParseDate(c Clock, date string) (time.Time, error) {
+	return time.ParseInLocation(time.DateOnly, date, c.Now().Location())
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// RootDir is the repository root as seen from the cmd directories the
+	// binaries run in. The .env file and every file kept between runs
+	// default to it.
+	RootDir = "../.."
+
+	DefaultPath = RootDir + "/config.json"
+)
+
+// TeamConfig is the lineup configuration for one Yahoo team.
+type TeamConfig struct {
+	TeamKey     string `json:"team_key"`
+	SkipGoalies bool   `json:"skip_goalies"`
+	Skaters     bool   `json:"skaters"`
+
+	// StarterPolicy is how unconfirmed starting goalies are treated:
+	// "confirmed", "projected" (the default) or "both"
+	StarterPolicy string `json:"starter_policy"`
+
+	// Projections ranks start/sit decisions by SportsData.io projected
+	// fantasy points
+	Projections bool `json:"projections"`
+
+	// InjuredReserve moves injured players in and out of IR slots
+	InjuredReserve bool `json:"injured_reserve"`
+
+	// StreamGoalies picks up a free-agent starter when none of the
+	// rostered goalies start, never dropping an untouchable goalie.
+	StreamGoalies      bool     `json:"stream_goalies"`
+	UntouchableGoalies []string `json:"untouchable_goalies"`
+
+	// RatioProtection benches goalies to protect GAA and SV% leads in
+	// head-to-head category leagues.
+	RatioProtection RatioProtection `json:"ratio_protection"`
+}
+
+// RatioProtection holds the risk thresholds for benching goalies. Zero
+// values use the defaults: 4 goals on 30 shots, one lead at risk and the
+// league's minimum goalie games.
+type RatioProtection struct {
+	Enabled              bool `json:"enabled"`
+	BadStartGoalsAgainst int  `json:"bad_start_goals_against"`
+	BadStartShots        int  `json:"bad_start_shots"`
+	CategoriesAtRisk     int  `json:"categories_at_risk"`
+	MinGames             int  `json:"min_games"`
+}
+
+type Config struct {
+	Teams []TeamConfig `json:"teams"`
+}
+
+// Path returns the config file location from CONFIG_PATH or the default.
+func Path() string {
+	if path := os.Getenv("CONFIG_PATH"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// Load reads the config file at path. Without one, the teams are taken
+// from YAHOO_TEAM_KEYS (comma separated) or YAHOO_LEAGUE_ID and
+// YAHOO_TEAM_ID.
+func Load(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return cfg, err
+	} else {
+		cfg.Teams = envTeams()
+	}
+
+	if len(cfg.Teams) == 0 {
+		return cfg, errors.New("no Yahoo teams configured")
+	}
+	for _, tc := range cfg.Teams {
+		if !strings.Contains(tc.TeamKey, ".t.") {
+			return cfg, fmt.Errorf("invalid team key %q", tc.TeamKey)
+		}
+	}
+	return cfg, nil
+}
+
+func envTeams() []TeamConfig {
+	var teams []TeamConfig
+	if keys := os.Getenv("YAHOO_TEAM_KEYS"); keys != "" {
+		for _, key := range strings.Split(keys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				teams = append(teams, TeamConfig{TeamKey: key})
+			}
+		}
+		return teams
+	}
+	if league, team := os.Getenv("YAHOO_LEAGUE_ID"), os.Getenv("YAHOO_TEAM_ID"); league != "" && team != "" {
+		teams = append(teams, TeamConfig{TeamKey: league + ".t." + team})
+	}
+	return teams
+}
@@ -5,6 +5,13 @@ import (
 	"os"
 )
 
+// Notify emails a failed response body when the client has email enabled.
+func Notify(enabled bool, respBody []byte) {
+	if enabled {
+		SendEmail(respBody)
+	}
+}
+
 func SendEmail(respBody []byte) {
 	from := os.Getenv("EMAIL_ADDRESS")
 	password := os.Getenv("EMAIL_PASSWORD")
@@ -0,0 +1,181 @@
+// Package fakeServer is an httptest server standing in for the Yahoo
+// Fantasy, Yahoo OAuth and SportsData.io APIs, serving recorded fixtures so
+// a full goalie run can be exercised without the network.
+package fakeServer
+
+import (
+	"embed"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+)
+
+//go:embed testdata
+var fixtures embed.FS
+
+// Fixture returns the contents of a recorded response in testdata.
+func Fixture(name string) []byte {
+	data, err := fixtures.ReadFile("testdata/" + name)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// Response is a canned reply to a matching request.
+type Response struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Request is a request the server received.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+type route struct {
+	method   string
+	contains string
+	resp     Response
+	// remaining is how many more requests the route answers, -1 for all
+	remaining int
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   []route
+	requests []Request
+}
+
+// New starts a server answering the token, league settings, game weeks,
+// team, roster, matchup, scoreboard, free agent, player, transactions,
+// starting goaltender, schedule, stadium, player projection and player
+// stats endpoints from the recorded fixtures. Close it when done.
+func New() *Server {
+	s := &Server{}
+	s.Handle(http.MethodPost, "/oauth2/get_token", Response{Body: Fixture("token.json"), ContentType: "application/json"})
+	s.Handle(http.MethodGet, "/settings", Response{Body: Fixture("league_settings.xml")})
+	s.Handle(http.MethodGet, "/game_weeks", Response{Body: Fixture("game_weeks.xml")})
+	s.Handle(http.MethodGet, "/team/", Response{Body: Fixture("team.xml")})
+	s.Handle(http.MethodGet, "/roster", Response{Body: Fixture("roster.xml")})
+	s.Handle(http.MethodPut, "/roster", Response{Body: Fixture("roster_put.xml")})
+	s.Handle(http.MethodGet, "/matchups", Response{Body: Fixture("matchups.xml")})
+	s.Handle(http.MethodGet, "/scoreboard", Response{Body: Fixture("scoreboard.xml")})
+	s.Handle(http.MethodGet, "/players;status=FA", Response{Body: Fixture("free_agents.xml")})
+	s.Handle(http.MethodGet, "/players;player_keys=", Response{Body: Fixture("players.xml")})
+	s.Handle(http.MethodGet, "/transactions", Response{Body: Fixture("transactions.xml")})
+	s.Handle(http.MethodPost, "/transactions", Response{Body: Fixture("transaction.xml")})
+	s.Handle(http.MethodGet, "/StartingGoaltendersByDate/", Response{Body: Fixture("starting_goaltenders.json"), ContentType: "application/json"})
+	s.Handle(http.MethodGet, "/GamesByDate/", Response{Body: Fixture("games_by_date.json"), ContentType: "application/json"})
+	s.Handle(http.MethodGet, "/json/Games/", Response{Body: Fixture("season_games.json"), ContentType: "application/json"})
+	s.Handle(http.MethodGet, "/Stadiums", Response{Body: Fixture("stadiums.json"), ContentType: "application/json"})
+	s.Handle(http.MethodGet, "/PlayerGameProjectionStatsByDate/", Response{Body: Fixture("player_projections.json"), ContentType: "application/json"})
+	s.Handle(http.MethodGet, "/PlayerGameStatsByDate/", Response{Body: Fixture("player_game_stats.json"), ContentType: "application/json"})
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Handle answers requests with the given method whose path contains the
+// given string. Later registrations take precedence, so tests can override
+// the defaults.
+func (s *Server) Handle(method string, contains string, resp Response) {
+	s.HandleN(method, contains, resp, -1)
+}
+
+// HandleN is Handle for only the next n matching requests, after which
+// earlier registrations answer again. Use it to fail a request once, e.g.
+// with a 401, and let the retry succeed.
+func (s *Server) HandleN(method string, contains string, resp Response, n int) {
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	if resp.ContentType == "" {
+		resp.ContentType = "application/xml"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes = append(s.routes, route{method: method, contains: contains, resp: resp, remaining: n})
+}
+
+// Requests returns every request received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the received requests with the given method whose path
+// contains the given string.
+func (s *Server) RequestsTo(method string, contains string) []Request {
+	var matched []Request
+	for _, r := range s.Requests() {
+		if r.Method == method && strings.Contains(r.Path, contains) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// YahooClient returns a Yahoo client pointed at the server.
+func (s *Server) YahooClient() *yahoo.YahooClient {
+	yc := yahoo.NewYahooClient(false)
+	yc.TokenStore = &yahoo.MemoryTokenStore{}
+	yc.Audit = nil
+	yc.HTTPClient = s.Client()
+	yc.BaseURL = s.URL + "/fantasy/v2"
+	yc.TokenURL = s.URL + "/oauth2/get_token"
+	return yc
+}
+
+// SportsDataClient returns a SportsData.io client pointed at the server.
+func (s *Server) SportsDataClient() *sportsData.Client {
+	c := sportsData.NewClient(false)
+	c.APIKey = "fake-key"
+	c.Cache = nil
+	c.HTTPClient = s.Client()
+	c.BaseURL = s.URL + "/v3/nhl"
+	return c
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	var resp *Response
+	for i := len(s.routes) - 1; i >= 0; i-- {
+		rt := &s.routes[i]
+		if rt.method == r.Method && rt.remaining != 0 && strings.Contains(r.URL.Path, rt.contains) {
+			if rt.remaining > 0 {
+				rt.remaining--
+			}
+			resp = &rt.resp
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if resp == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", resp.ContentType)
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
@@ -1,26 +1,44 @@
 package goalies
 
 import (
+	"hockey-hacks/pkg/playerMap"
 	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
 	"log"
-	"os"
 )
 
-func GetTeamStartingGoalies(games sportsData.Games) sportsData.Goalies {
+// GetTeamStartingGoalies returns the projected starters for every game
+// involving the NHL team of one of the rostered goalies, plus any starter
+// mapped to a rostered goalie whose Yahoo team is out of date. Starters on
+// those teams are matched to the roster and recorded in the player map.
+func GetTeamStartingGoalies(games sportsData.Games, rosterGoalies []yahoo.Player, ids *playerMap.Map) sportsData.Goalies {
+	rosterTeams := make(map[string]bool)
+	for _, team := range yahoo.Teams(rosterGoalies) {
+		rosterTeams[team] = true
+	}
+	rosterKeys := make(map[string]bool)
+	for _, p := range rosterGoalies {
+		rosterKeys[p.PlayerKey] = true
+	}
+	candidates := yahoo.Candidates(rosterGoalies)
+
 	var startingGoalies sportsData.Goalies
-	team1 := os.Getenv("TEAM1_ABBR")
-	team2 := os.Getenv("TEAM2_ABBR")
-	for _, n := range games {
-		if n.HomeTeam == team1 {
-			startingGoalies = append(startingGoalies, n.HomeGoaltender)
-		} else if n.HomeTeam == team2 {
-			startingGoalies = append(startingGoalies, n.HomeGoaltender)
+	add := func(goalie sportsData.Goalie, team string) {
+		if goalie.Team == "" {
+			goalie.Team = team
 		}
-		if n.AwayTeam == team1 {
-			startingGoalies = append(startingGoalies, n.AwayGoaltender)
-		} else if n.AwayTeam == team2 {
-			startingGoalies = append(startingGoalies, n.AwayGoaltender)
+		if key, ok := ids.YahooKey(goalie.PlayerID); ok && rosterKeys[key] {
+			startingGoalies = append(startingGoalies, goalie)
+			return
 		}
+		if rosterTeams[goalie.Team] {
+			ids.Resolve(goalie.PlayerID, goalie.FirstName, goalie.LastName, goalie.Team, candidates)
+			startingGoalies = append(startingGoalies, goalie)
+		}
+	}
+	for _, n := range games {
+		add(n.HomeGoaltender, n.HomeTeam)
+		add(n.AwayGoaltender, n.AwayTeam)
 	}
 	log.Println(startingGoalies)
 	return startingGoalies
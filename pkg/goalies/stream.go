@@ -0,0 +1,164 @@
+package goalies
+
+import (
+	"hockey-hacks/pkg/playerMap"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"log"
+)
+
+// StreamOptions limits goalie streaming for a team.
+type StreamOptions struct {
+	// Budget is the team's weekly add budget
+	Budget yahoo.AddBudget
+	// Untouchable lists the Yahoo keys of goalies that are never dropped
+	Untouchable []string
+	// DryRun picks the goalies without making the transaction
+	DryRun bool
+}
+
+// Stream adds the best ranked free-agent goalie confirmed to start in one of
+// the games that have not started yet and drops the lowest-owned rostered
+// goalie for them, when none of the rostered goalies is projected to start.
+// It returns nil when no pickup is needed or possible. Nothing is streamed
+// while an unmapped starter's name is close to one of the rostered goalies
+// on the same team, since the start may be ours.
+func Stream(yc *yahoo.YahooClient, team yahoo.Team, games sportsData.Games, ids *playerMap.Map, opts StreamOptions) (*yahoo.Pickup, error) {
+	roster := team.Roster.Players
+	starts, unresolved := rosterStarts(GetTeamStartingGoalies(games, roster.Goalies(), ids), roster, ids)
+	if starts {
+		return nil, nil
+	}
+	if len(unresolved) > 0 {
+		log.Printf("%s: Not streaming, starters %v are not in the player map and may be rostered goalies", team.TeamKey, unresolved)
+		return nil, nil
+	}
+	if !opts.Budget.CanAdd() {
+		log.Printf("%s: No goalie starts, but %s", team.TeamKey, opts.Budget)
+		return nil, nil
+	}
+
+	drop, err := weakestGoalie(yc, team, opts.Untouchable)
+	if err != nil || drop == nil {
+		return nil, err
+	}
+
+	freeAgents, err := yc.GetFreeAgents(yahoo.LeagueKey(team.TeamKey), yahoo.PositionGoalie)
+	if err != nil {
+		return nil, err
+	}
+	// A goalie whose game has started is locked out of the lineup
+	add := bestStarter(freeAgents, games.NotStarted(yc.Clock.Now()), ids)
+	if add == nil {
+		log.Printf("%s: No goalie starts and no confirmed starter is a free agent", team.TeamKey)
+		return nil, nil
+	}
+
+	pickup := &yahoo.Pickup{
+		Add:      add.PlayerKey,
+		AddName:  add.Name.Full,
+		Drop:     drop.PlayerKey,
+		DropName: drop.Name.Full,
+		Reason:   "no rostered goalie starts, confirmed starter available",
+	}
+	if opts.DryRun {
+		log.Printf("%s: Dry run, not streaming %s for %s", team.TeamKey, pickup.AddName, pickup.DropName)
+		return pickup, nil
+	}
+
+	tr, err := yc.Transact(team.TeamKey, yahoo.TransactionRequest{Add: add.PlayerKey, Drop: drop.PlayerKey})
+	if err != nil {
+		return nil, err
+	}
+	pickup.TransactionKey = tr.TransactionKey
+	pickup.Status = tr.Status
+	return pickup, nil
+}
+
+// rosterStarts reports whether any of the starters is on the roster. It
+// also returns the unmapped starters whose names are close to a rostered
+// goalie of their team, who may be rostered goalies the fuzzy match did not
+// dare to map. Other unmapped starters, such as a backup starting over a
+// rostered goalie, are not ours.
+func rosterStarts(starters sportsData.Goalies, roster yahoo.Players, ids *playerMap.Map) (bool, []string) {
+	rosterKeys := make(map[string]bool)
+	for _, p := range roster.PlayerList {
+		rosterKeys[p.PlayerKey] = true
+	}
+	candidates := yahoo.Candidates(roster.Goalies())
+	var unresolved []string
+	for _, g := range starters {
+		key, ok := ids.YahooKey(g.PlayerID)
+		if ok && rosterKeys[key] {
+			return true, nil
+		}
+		if !ok && playerMap.Plausible(g.FirstName, g.LastName, g.Team, candidates) {
+			unresolved = append(unresolved, g.FirstName+" "+g.LastName+" ("+g.Team+")")
+		}
+	}
+	return false, unresolved
+}
+
+// bestStarter returns the best ranked free agent who is a confirmed starter.
+// Free agents are expected in rank order.
+func bestStarter(freeAgents yahoo.Players, games sportsData.Games, ids *playerMap.Map) *yahoo.Player {
+	candidates := yahoo.Candidates(freeAgents.PlayerList)
+	confirmed := make(map[string]bool)
+	check := func(goalie sportsData.Goalie, team string) {
+		if !goalie.Confirmed {
+			return
+		}
+		if goalie.Team == "" {
+			goalie.Team = team
+		}
+		if key, ok := ids.YahooKey(goalie.PlayerID); ok {
+			confirmed[key] = true
+		} else if key, ok := ids.Resolve(goalie.PlayerID, goalie.FirstName, goalie.LastName, goalie.Team, candidates); ok {
+			confirmed[key] = true
+		}
+	}
+	for _, n := range games {
+		check(n.HomeGoaltender, n.HomeTeam)
+		check(n.AwayGoaltender, n.AwayTeam)
+	}
+
+	for i, p := range freeAgents.PlayerList {
+		if confirmed[p.PlayerKey] {
+			return &freeAgents.PlayerList[i]
+		}
+	}
+	return nil
+}
+
+// weakestGoalie returns the lowest-owned rostered goalie that may be
+// dropped, or nil when every goalie is untouchable, undroppable or injured.
+func weakestGoalie(yc *yahoo.YahooClient, team yahoo.Team, untouchable []string) (*yahoo.Player, error) {
+	skip := make(map[string]bool)
+	for _, key := range untouchable {
+		skip[key] = true
+	}
+	var keys []string
+	for _, p := range team.Roster.Players.Goalies() {
+		// Dropping an injured goalie would not free an active roster spot
+		if skip[p.PlayerKey] || !p.IsDroppable() || p.IsInjuredReserve() {
+			continue
+		}
+		keys = append(keys, p.PlayerKey)
+	}
+	if len(keys) == 0 {
+		log.Printf("%s: No goalie can be dropped to stream a starter", team.TeamKey)
+		return nil, nil
+	}
+
+	owned, err := yc.GetPlayers(yahoo.LeagueKey(team.TeamKey), keys)
+	if err != nil {
+		return nil, err
+	}
+	var weakest *yahoo.Player
+	for i, p := range owned.PlayerList {
+		if weakest == nil || p.PercentOwned.Value < weakest.PercentOwned.Value {
+			weakest = &owned.PlayerList[i]
+		}
+	}
+	return weakest, nil
+}
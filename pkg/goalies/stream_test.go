@@ -0,0 +1,87 @@
+package goalies
+
+import (
+	"hockey-hacks/pkg/fakeServer"
+	"hockey-hacks/pkg/playerMap"
+	"hockey-hacks/pkg/yahoo"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const teamKey = "465.l.1234.t.8"
+
+// stream runs Stream for the fixture team with the given starting goalies.
+func stream(t *testing.T, startingGoalies string) (*yahoo.Pickup, *fakeServer.Server) {
+	t.Helper()
+	s := fakeServer.New()
+	t.Cleanup(s.Close)
+	s.Handle(http.MethodGet, "/StartingGoaltendersByDate/", fakeServer.Response{Body: []byte(startingGoalies), ContentType: "application/json"})
+
+	yc := s.YahooClient()
+	sd := s.SportsDataClient()
+	date := time.Date(2024, 10, 19, 0, 0, 0, 0, time.UTC)
+	games, err := sd.GetStartingGoalies(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	team, err := yc.GetTeamRoster(teamKey, yahoo.DateCoverage(date))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := playerMap.Load(filepath.Join(t.TempDir(), "player_map.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pickup, err := Stream(yc, team, games, ids, StreamOptions{Budget: yahoo.NewAddBudget(yahoo.League{}, team)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pickup, s
+}
+
+func TestStreamPicksConfirmedStarter(t *testing.T) {
+	pickup, s := stream(t, `[{"GameID": 1, "HomeTeam": "NYR", "AwayTeam": "NJ",
+		"HomeGoaltender": {"PlayerID": 30002211, "Team": "NYR", "FirstName": "Igor", "LastName": "Shesterkin", "Confirmed": true}}]`)
+	if pickup == nil {
+		t.Fatal("no pickup")
+	}
+	if pickup.AddName != "Igor Shesterkin" || pickup.DropName != "Joonas Korpisalo" {
+		t.Errorf("picked up %s for %s, want Igor Shesterkin for Joonas Korpisalo", pickup.AddName, pickup.DropName)
+	}
+	if n := len(s.RequestsTo(http.MethodPost, "/transactions")); n != 1 {
+		t.Errorf("got %d transaction POSTs, want 1", n)
+	}
+}
+
+func TestStreamBackupStartsOverRosteredGoalies(t *testing.T) {
+	// A TOR backup nobody rosters starts over Stolarz and Woll, so no
+	// rostered goalie plays and the confirmed NYR starter is picked up.
+	pickup, s := stream(t, `[{"GameID": 1, "HomeTeam": "TOR", "AwayTeam": "NYR",
+		"HomeGoaltender": {"PlayerID": 39999999, "Team": "TOR", "FirstName": "Dennis", "LastName": "Hildeby", "Confirmed": true},
+		"AwayGoaltender": {"PlayerID": 30002211, "Team": "NYR", "FirstName": "Igor", "LastName": "Shesterkin", "Confirmed": true}}]`)
+	if pickup == nil {
+		t.Fatal("no pickup while a backup starts over the rostered goalies")
+	}
+	if pickup.AddName != "Igor Shesterkin" {
+		t.Errorf("picked up %s, want Igor Shesterkin", pickup.AddName)
+	}
+	if n := len(s.RequestsTo(http.MethodPost, "/transactions")); n != 1 {
+		t.Errorf("got %d transaction POSTs, want 1", n)
+	}
+}
+
+func TestStreamSkipsPlausibleUnmappedStarter(t *testing.T) {
+	// "Joe Wol" is too far from Joseph Woll to map, but too close to rule
+	// out, so the TOR start may be ours.
+	pickup, s := stream(t, `[{"GameID": 1, "HomeTeam": "TOR", "AwayTeam": "NYR",
+		"HomeGoaltender": {"PlayerID": 39999999, "Team": "TOR", "FirstName": "Joe", "LastName": "Wol", "Confirmed": true},
+		"AwayGoaltender": {"PlayerID": 30002211, "Team": "NYR", "FirstName": "Igor", "LastName": "Shesterkin", "Confirmed": true}}]`)
+	if pickup != nil {
+		t.Errorf("streamed %s for %s on an unresolved starter", pickup.AddName, pickup.DropName)
+	}
+	if n := len(s.RequestsTo(http.MethodPost, "/transactions")); n != 0 {
+		t.Errorf("got %d transaction POSTs, want none", n)
+	}
+}
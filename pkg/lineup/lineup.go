@@ -0,0 +1,200 @@
+package lineup
+
+import (
+	"hockey-hacks/pkg/yahoo"
+	"log"
+	"sort"
+)
+
+// Slot is an active roster position and how many of it the lineup has.
+type Slot struct {
+	Position string
+	Count    int
+}
+
+// DefaultSlots is the standard Yahoo skater lineup.
+var DefaultSlots = []Slot{
+	{Position: "C", Count: 2},
+	{Position: "LW", Count: 2},
+	{Position: "RW", Count: 2},
+	{Position: "D", Count: 4},
+	{Position: yahoo.PositionUtil, Count: 1},
+}
+
+// Slots returns the league's active skater slots, or DefaultSlots when the
+// settings list no roster positions.
+func Slots(settings yahoo.LeagueSettings) []Slot {
+	positions := settings.StartingPositions(yahoo.PositionTypeSkater)
+	if len(positions) == 0 {
+		return DefaultSlots
+	}
+	var slots []Slot
+	for _, rp := range positions {
+		slots = append(slots, Slot{Position: rp.Position, Count: rp.Count})
+	}
+	return slots
+}
+
+// flexPositions lists the base positions each flex slot accepts.
+var flexPositions = map[string][]string{
+	yahoo.PositionUtil: {"C", "LW", "RW", "D"},
+	yahoo.PositionFwd:  {"C", "LW", "RW"},
+}
+
+func eligible(p yahoo.Player, position string) bool {
+	if p.IsEligible(position) {
+		return true
+	}
+	for _, base := range flexPositions[position] {
+		if p.IsEligible(base) {
+			return true
+		}
+	}
+	return false
+}
+
+// Optimize assigns skaters whose team plays today to the active slots so
+// that as many of them start as possible. Skaters are treated as a
+// bipartite matching against individual slots, so a multi-position player
+// is moved to make room for a single-position one when that adds a start.
+// Skaters who do not play keep their slot unless a playing skater needs it.
+// It returns the new position of every skater it manages.
+func Optimize(roster yahoo.Players, playing map[string]bool, slots []Slot) []yahoo.Move {
+	return OptimizeProjected(roster, playing, slots, nil, nil)
+}
+
+// OptimizeProjected is Optimize, but when there are more playing skaters
+// than slots the ones with the most projected points start. Slots are
+// filled in order of projected points, which starts the most valuable set
+// of skaters that fits the lineup. Locked skaters keep their slot and the
+// rest are matched to the slots left.
+func OptimizeProjected(roster yahoo.Players, playing map[string]bool, slots []Slot, projected yahoo.Projected, locked map[string]bool) []yahoo.Move {
+	var skaters []yahoo.Player
+	for _, p := range roster.PlayerList {
+		if !p.IsGoalie() && !p.IsInjuredReserve() {
+			skaters = append(skaters, p)
+		}
+	}
+
+	var instances []string
+	for _, s := range slots {
+		for i := 0; i < s.Count; i++ {
+			instances = append(instances, s.Position)
+		}
+	}
+
+	// match[i] is the index in skaters of the player holding instance i
+	match := make([]int, len(instances))
+	for i := range match {
+		match[i] = -1
+	}
+	assigned := make([]int, len(skaters))
+	for i := range assigned {
+		assigned[i] = -1
+	}
+
+	keep := func(pi int) {
+		p := skaters[pi]
+		for i, pos := range instances {
+			if match[i] == -1 && pos == p.SelectedPosition.Position && eligible(p, pos) {
+				match[i], assigned[pi] = pi, i
+				return
+			}
+		}
+	}
+	// Locked skaters hold their slot whatever their eligibility says.
+	for pi, p := range skaters {
+		if !locked[p.PlayerKey] {
+			continue
+		}
+		for i, pos := range instances {
+			if match[i] == -1 && pos == p.SelectedPosition.Position {
+				match[i], assigned[pi] = pi, i
+				break
+			}
+		}
+	}
+	// Seed the matching with playing skaters who already hold a slot so
+	// that augmenting paths only move them when it gains a start. With
+	// projections the most valuable skaters go first instead, keeping
+	// their slot when it is still open.
+	order := make([]int, 0, len(skaters))
+	for pi, p := range skaters {
+		if playing[p.Team()] && !locked[p.PlayerKey] {
+			order = append(order, pi)
+		}
+	}
+	if projected == nil {
+		for _, pi := range order {
+			keep(pi)
+		}
+	} else {
+		sort.SliceStable(order, func(i, j int) bool {
+			vi, _ := projected.Value(skaters[order[i]].PlayerKey)
+			vj, _ := projected.Value(skaters[order[j]].PlayerKey)
+			return vi > vj
+		})
+	}
+
+	var visited []bool
+	var augment func(pi int) bool
+	augment = func(pi int) bool {
+		for i, pos := range instances {
+			if visited[i] || !eligible(skaters[pi], pos) || match[i] != -1 && locked[skaters[match[i]].PlayerKey] {
+				continue
+			}
+			visited[i] = true
+			if match[i] == -1 || augment(match[i]) {
+				match[i], assigned[pi] = pi, i
+				return true
+			}
+		}
+		return false
+	}
+	for _, pi := range order {
+		if assigned[pi] != -1 {
+			continue
+		}
+		if keep(pi); assigned[pi] != -1 {
+			continue
+		}
+		visited = make([]bool, len(instances))
+		augment(pi)
+	}
+
+	// Leave idle skaters where they are while their slot is still open.
+	for pi, p := range skaters {
+		if playing[p.Team()] || locked[p.PlayerKey] {
+			continue
+		}
+		for i, pos := range instances {
+			if match[i] == -1 && pos == p.SelectedPosition.Position {
+				match[i], assigned[pi] = pi, i
+				break
+			}
+		}
+	}
+
+	var moves []yahoo.Move
+	starts := 0
+	for pi, p := range skaters {
+		if assigned[pi] != -1 && playing[p.Team()] {
+			starts++
+		}
+		switch {
+		case locked[p.PlayerKey]:
+			moves = append(moves, yahoo.LockedMove(p))
+		case assigned[pi] != -1 && playing[p.Team()]:
+			moves = append(moves, yahoo.NewMove(p, instances[assigned[pi]], projected.Describe(p.PlayerKey, "team plays")))
+		case assigned[pi] != -1:
+			moves = append(moves, yahoo.NewMove(p, instances[assigned[pi]], "team not playing, slot not needed"))
+		case playing[p.Team()]:
+			moves = append(moves, yahoo.NewMove(p, yahoo.PositionBench, projected.Describe(p.PlayerKey, "no eligible slot open")))
+		default:
+			moves = append(moves, yahoo.NewMove(p, yahoo.PositionBench, "team not playing"))
+		}
+	}
+	log.Printf("Lineup starts %d skaters", starts)
+
+	return moves
+}
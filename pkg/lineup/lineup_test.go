@@ -0,0 +1,144 @@
+package lineup_test
+
+import (
+	"hockey-hacks/pkg/lineup"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"testing"
+)
+
+// skater returns a rostered skater of the team in the slot, eligible for the
+// positions.
+func skater(name, team, slot string, positions ...string) yahoo.Player {
+	p := yahoo.Player{PlayerKey: name, EditorialTeamAbbr: team}
+	p.Name.Full = name
+	p.EligiblePositions.Positions = positions
+	p.SelectedPosition.Position = slot
+	return p
+}
+
+func TestOptimizeProjected(t *testing.T) {
+	tests := []struct {
+		name      string
+		roster    []yahoo.Player
+		slots     []lineup.Slot
+		projected map[string]float64
+		locked    []string
+		want      map[string]string
+	}{
+		{
+			name: "multi-position skater moves over",
+			roster: []yahoo.Player{
+				skater("Flex", "TOR", "C", "C", "LW"),
+				skater("Center", "BOS", yahoo.PositionBench, "C"),
+			},
+			slots: []lineup.Slot{{Position: "C", Count: 1}, {Position: "LW", Count: 1}},
+			want:  map[string]string{"Flex": "LW", "Center": "C"},
+		},
+		{
+			name: "idle skater gives up a needed slot",
+			roster: []yahoo.Player{
+				skater("Idle", "MTL", "C", "C"),
+				skater("Center", "BOS", yahoo.PositionBench, "C"),
+			},
+			slots: []lineup.Slot{{Position: "C", Count: 1}},
+			want:  map[string]string{"Idle": yahoo.PositionBench, "Center": "C"},
+		},
+		{
+			name: "idle skater keeps an unneeded slot",
+			roster: []yahoo.Player{
+				skater("Idle", "MTL", "C", "C"),
+				skater("Wing", "BOS", yahoo.PositionBench, "LW"),
+			},
+			slots: []lineup.Slot{{Position: "C", Count: 1}, {Position: "LW", Count: 1}},
+			want:  map[string]string{"Idle": "C", "Wing": "LW"},
+		},
+		{
+			name: "tie keeps the skater holding the slot",
+			roster: []yahoo.Player{
+				skater("Bench", "BOS", yahoo.PositionBench, "C"),
+				skater("Holder", "TOR", "C", "C"),
+			},
+			slots: []lineup.Slot{{Position: "C", Count: 1}},
+			want:  map[string]string{"Holder": "C", "Bench": yahoo.PositionBench},
+		},
+		{
+			name: "low projection gives up its slot",
+			roster: []yahoo.Player{
+				skater("Low", "TOR", "C", "C"),
+				skater("High", "BOS", yahoo.PositionBench, "C"),
+			},
+			slots:     []lineup.Slot{{Position: "C", Count: 1}},
+			projected: map[string]float64{"Low": 1, "High": 5},
+			want:      map[string]string{"High": "C", "Low": yahoo.PositionBench},
+		},
+		{
+			name: "projections fill a flex slot by value",
+			roster: []yahoo.Player{
+				skater("Low", "TOR", yahoo.PositionUtil, "D"),
+				skater("Mid", "BOS", yahoo.PositionBench, "C"),
+				skater("High", "NYR", yahoo.PositionBench, "LW"),
+			},
+			slots:     []lineup.Slot{{Position: yahoo.PositionUtil, Count: 1}},
+			projected: map[string]float64{"Low": 1, "Mid": 3, "High": 5},
+			want:      map[string]string{"High": yahoo.PositionUtil, "Mid": yahoo.PositionBench, "Low": yahoo.PositionBench},
+		},
+		{
+			name: "locked skater keeps the slot",
+			roster: []yahoo.Player{
+				skater("Locked", "TOR", "C", "C"),
+				skater("High", "BOS", yahoo.PositionBench, "C"),
+			},
+			slots:     []lineup.Slot{{Position: "C", Count: 1}},
+			projected: map[string]float64{"Locked": 1, "High": 5},
+			locked:    []string{"Locked"},
+			want:      map[string]string{"Locked": "C", "High": yahoo.PositionBench},
+		},
+		{
+			name: "locked skater is not moved to make room",
+			roster: []yahoo.Player{
+				skater("Locked", "TOR", "C", "C", "LW"),
+				skater("Center", "BOS", yahoo.PositionBench, "C"),
+			},
+			slots:  []lineup.Slot{{Position: "C", Count: 1}, {Position: "LW", Count: 1}},
+			locked: []string{"Locked"},
+			want:   map[string]string{"Locked": "C", "Center": yahoo.PositionBench},
+		},
+		{
+			name: "locked bench skater stays benched",
+			roster: []yahoo.Player{
+				skater("Locked", "TOR", yahoo.PositionBench, "C"),
+			},
+			slots:  []lineup.Slot{{Position: "C", Count: 1}},
+			locked: []string{"Locked"},
+			want:   map[string]string{"Locked": yahoo.PositionBench},
+		},
+	}
+	playing := map[string]bool{"TOR": true, "BOS": true, "NYR": true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var projected yahoo.Projected
+			if tt.projected != nil {
+				projected = make(yahoo.Projected)
+				for key, points := range tt.projected {
+					projected[key] = sportsData.Player{FantasyPointsYahoo: points}
+				}
+			}
+			locked := make(map[string]bool)
+			for _, key := range tt.locked {
+				locked[key] = true
+			}
+
+			moves := lineup.OptimizeProjected(yahoo.Players{PlayerList: tt.roster}, playing, tt.slots, projected, locked)
+			got := make(map[string]string)
+			for _, m := range moves {
+				got[m.Name] = m.Position
+			}
+			for name, pos := range tt.want {
+				if got[name] != pos {
+					t.Errorf("%s moved to %q, want %q", name, got[name], pos)
+				}
+			}
+		})
+	}
+}
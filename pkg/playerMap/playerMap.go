@@ -0,0 +1,273 @@
+package playerMap
+
+import (
+	"encoding/json"
+	"errors"
+	"hockey-hacks/pkg/config"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultPath = config.RootDir + "/player_map.json"
+
+	// Minimum fuzzy score for an automatic match
+	matchThreshold = 0.9
+	// Required gap between the best and second best candidate
+	matchMargin = 0.1
+	// Minimum fuzzy score for a candidate that cannot be ruled out
+	plausibleThreshold = 0.7
+)
+
+// Entry links a SportsData.io player to a Yahoo player key.
+type Entry struct {
+	SportsDataID int    `json:"sports_data_id"`
+	YahooKey     string `json:"yahoo_key"`
+	Name         string `json:"name"`
+	Team         string `json:"team"`
+	Manual       bool   `json:"manual"`
+}
+
+// Candidate is a Yahoo player that a SportsData.io player may be matched to.
+type Candidate struct {
+	Key   string
+	First string
+	Last  string
+	Team  string
+}
+
+// Map is the persisted SportsData.io to Yahoo player mapping.
+type Map struct {
+	path    string
+	mu      sync.Mutex
+	entries map[int]Entry
+	dirty   bool
+}
+
+// Path returns the map file location from PLAYER_MAP_PATH or the default.
+func Path() string {
+	if path := os.Getenv("PLAYER_MAP_PATH"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// Load reads the map at path. A missing file yields an empty map.
+func Load(path string) (*Map, error) {
+	m := &Map{path: path, entries: make(map[int]Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		m.entries[e.SportsDataID] = e
+	}
+	return m, nil
+}
+
+// Save writes the map back to disk if it has changed.
+func (m *Map) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.dirty {
+		return nil
+	}
+	data, err := json.MarshalIndent(m.list(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(m.path, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	m.dirty = false
+	return nil
+}
+
+// Entries returns every mapping ordered by name.
+func (m *Map) Entries() []Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.list()
+}
+
+func (m *Map) list() []Entry {
+	entries := make([]Entry, 0, len(m.entries))
+	for _, e := range m.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].SportsDataID < entries[j].SportsDataID
+	})
+	return entries
+}
+
+// YahooKey returns the Yahoo player key mapped to a SportsData.io player.
+func (m *Map) YahooKey(sportsDataID int) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[sportsDataID]
+	return e.YahooKey, ok
+}
+
+// Set adds or replaces a mapping.
+func (m *Map) Set(e Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[e.SportsDataID] = e
+	m.dirty = true
+}
+
+// Remove deletes a mapping and reports whether it existed.
+func (m *Map) Remove(sportsDataID int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.entries[sportsDataID]; !ok {
+		return false
+	}
+	delete(m.entries, sportsDataID)
+	m.dirty = true
+	return true
+}
+
+// Resolve returns the Yahoo key for a SportsData.io player, fuzzy matching
+// it against the candidates and remembering the result when it is not
+// mapped yet. Existing entries, including manual corrections, always win.
+func (m *Map) Resolve(sportsDataID int, first, last, team string, candidates []Candidate) (string, bool) {
+	if key, ok := m.YahooKey(sportsDataID); ok {
+		return key, true
+	}
+
+	best, bestScore, secondScore := -1, 0.0, 0.0
+	for i, c := range candidates {
+		score := matchScore(first, last, team, c)
+		if score > bestScore {
+			best, bestScore, secondScore = i, score, bestScore
+		} else if score > secondScore {
+			secondScore = score
+		}
+	}
+	if best == -1 || bestScore < matchThreshold || bestScore-secondScore < matchMargin {
+		log.Printf("No player map match for %s %s (%s)", first, last, team)
+		return "", false
+	}
+
+	c := candidates[best]
+	m.Set(Entry{
+		SportsDataID: sportsDataID,
+		YahooKey:     c.Key,
+		Name:         first + " " + last,
+		Team:         team,
+	})
+	log.Printf("Mapped %s %s (%d) to %s", first, last, sportsDataID, c.Key)
+	return c.Key, true
+}
+
+// Plausible reports whether the player could be one of the candidates that
+// Resolve did not dare to match, because one of them scores close to it.
+func Plausible(first, last, team string, candidates []Candidate) bool {
+	for _, c := range candidates {
+		if matchScore(first, last, team, c) >= plausibleThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// matchScore rates how likely a candidate is the same person, mostly on
+// last name with the first name and team as supporting evidence.
+func matchScore(first, last, team string, c Candidate) float64 {
+	lastScore := similarity(normalize(last), normalize(c.Last))
+	firstScore := similarity(normalize(first), normalize(c.First))
+	// Allow shortened first names such as Alex for Alexandar
+	f, cf := normalize(first), normalize(c.First)
+	if len(f) >= 3 && len(cf) >= 3 && (strings.HasPrefix(f, cf) || strings.HasPrefix(cf, f)) {
+		firstScore = 1
+	}
+
+	score := 0.6*lastScore + 0.4*firstScore
+	if team != "" && team == c.Team {
+		score += 0.1
+	}
+	return score
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "å", "a", "ã", "a",
+	"č", "c", "ç", "c", "ć", "c",
+	"é", "e", "è", "e", "ê", "e", "ë", "e", "ě", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ñ", "n", "ň", "n",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "ø", "o", "õ", "o",
+	"ř", "r", "š", "s", "ť", "t",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u", "ů", "u",
+	"ý", "y", "ÿ", "y", "ž", "z", "ł", "l", "ß", "ss",
+)
+
+// normalize lowercases a name and strips accents and punctuation.
+func normalize(name string) string {
+	name = accents.Replace(strings.ToLower(name))
+	var b strings.Builder
+	for _, r := range name {
+		if r >= 'a' && r <= 'z' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// similarity returns 1 minus the normalized edit distance of a and b.
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
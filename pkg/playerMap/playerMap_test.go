@@ -0,0 +1,99 @@
+package playerMap
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		name              string
+		first, last, team string
+		c                 Candidate
+		min, max          float64
+	}{
+		{"same name and team", "Connor", "McDavid", "EDM", Candidate{First: "Connor", Last: "McDavid", Team: "EDM"}, 1.1, 1.1},
+		{"same name, other team", "Connor", "McDavid", "EDM", Candidate{First: "Connor", Last: "McDavid", Team: "TOR"}, 1, 1},
+		{"diacritics", "Šimon", "Nemec", "NJ", Candidate{First: "Simon", Last: "Nemec", Team: "NJ"}, 1.1, 1.1},
+		{"punctuation and case", "J.T.", "Miller", "VAN", Candidate{First: "JT", Last: "miller", Team: "VAN"}, 1.1, 1.1},
+		{"shortened first name", "Alex", "Georgiev", "SJ", Candidate{First: "Alexandar", Last: "Georgiev", Team: "SJ"}, 1.1, 1.1},
+		{"same last name, other first name", "Quinton", "Hughes", "VAN", Candidate{First: "Jack", Last: "Hughes", Team: "NJ"}, 0.6, 0.7},
+		{"different player", "Dennis", "Hildeby", "TOR", Candidate{First: "Joseph", Last: "Woll", Team: "TOR"}, 0, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := matchScore(tt.first, tt.last, tt.team, tt.c)
+			if got < tt.min-1e-9 || got > tt.max+1e-9 {
+				t.Errorf("matchScore = %.3f, want between %.2f and %.2f", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	woll := Candidate{Key: "465.p.8640", First: "Joseph", Last: "Woll", Team: "TOR"}
+	stolarz := Candidate{Key: "465.p.5734", First: "Anthony", Last: "Stolarz", Team: "TOR"}
+	tests := []struct {
+		name              string
+		first, last, team string
+		candidates        []Candidate
+		want              string
+	}{
+		{"exact match", "Joseph", "Woll", "TOR", []Candidate{stolarz, woll}, woll.Key},
+		{"accented spelling", "Jośeph", "Wöll", "TOR", []Candidate{stolarz, woll}, woll.Key},
+		{"below threshold", "Joe", "Wol", "TOR", []Candidate{stolarz, woll}, ""},
+		{"no candidates", "Joseph", "Woll", "TOR", nil, ""},
+		{
+			name: "tie between namesakes", first: "Sebastian", last: "Aho",
+			candidates: []Candidate{
+				{Key: "465.p.6745", First: "Sebastian", Last: "Aho", Team: "CAR"},
+				{Key: "465.p.7571", First: "Sebastian", Last: "Aho", Team: "NYI"},
+			},
+			want: "",
+		},
+		{
+			name: "within the margin", first: "Jon", last: "Smith", team: "NSH",
+			candidates: []Candidate{
+				{Key: "465.p.1", First: "John", Last: "Smith", Team: "NSH"},
+				{Key: "465.p.2", First: "Jon", Last: "Smyth", Team: "NSH"},
+			},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Load(filepath.Join(t.TempDir(), "player_map.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, ok := m.Resolve(30000001, tt.first, tt.last, tt.team, tt.candidates)
+			if key != tt.want || ok != (tt.want != "") {
+				t.Errorf("Resolve = %q, %t, want %q", key, ok, tt.want)
+			}
+			if stored, ok := m.YahooKey(30000001); ok != (tt.want != "") || stored != tt.want {
+				t.Errorf("remembered %q, %t, want %q", stored, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveKeepsManualEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "player_map.json")
+	m, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Set(Entry{SportsDataID: 30004567, YahooKey: "465.p.5734", Name: "Joseph Woll", Manual: true})
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	woll := Candidate{Key: "465.p.8640", First: "Joseph", Last: "Woll", Team: "TOR"}
+	if key, _ := m.Resolve(30004567, "Joseph", "Woll", "TOR", []Candidate{woll}); key != "465.p.5734" {
+		t.Errorf("Resolve = %q, want the manual correction 465.p.5734", key)
+	}
+}
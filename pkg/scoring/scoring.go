@@ -0,0 +1,124 @@
+// Package scoring computes fantasy points the way a Yahoo points league
+// scores them, from SportsData.io stat lines.
+package scoring
+
+import (
+	"errors"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"sort"
+)
+
+// ErrNoModifiers is returned for leagues that award no points per stat,
+// such as category leagues.
+var ErrNoModifiers = errors.New("league has no stat modifiers")
+
+// stat is how a Yahoo NHL stat is read from a stat line.
+type stat struct {
+	name  string
+	value func(p sportsData.Player) float64
+}
+
+// stats maps Yahoo NHL stat IDs to their value in a stat line.
+var stats = map[int]stat{
+	1:  {"G", func(p sportsData.Player) float64 { return p.Goals }},
+	2:  {"A", func(p sportsData.Player) float64 { return p.Assists }},
+	3:  {"P", func(p sportsData.Player) float64 { return p.Goals + p.Assists }},
+	4:  {"+/-", func(p sportsData.Player) float64 { return p.PlusMinus }},
+	5:  {"PIM", func(p sportsData.Player) float64 { return p.PenaltyMinutes }},
+	6:  {"PPG", func(p sportsData.Player) float64 { return p.PowerPlayGoals }},
+	7:  {"PPA", func(p sportsData.Player) float64 { return p.PowerPlayAssists }},
+	8:  {"PPP", func(p sportsData.Player) float64 { return p.PowerPlayGoals + p.PowerPlayAssists }},
+	9:  {"SHG", func(p sportsData.Player) float64 { return p.ShortHandedGoals }},
+	10: {"SHA", func(p sportsData.Player) float64 { return p.ShortHandedAssists }},
+	11: {"SHP", func(p sportsData.Player) float64 { return p.ShortHandedGoals + p.ShortHandedAssists }},
+	14: {"SOG", func(p sportsData.Player) float64 { return p.ShotsOnGoal }},
+	31: {"HIT", func(p sportsData.Player) float64 { return p.Hits }},
+	32: {"BLK", func(p sportsData.Player) float64 { return p.Blocks }},
+
+	yahoo.StatGoalieStarts: {"GS", func(p sportsData.Player) float64 {
+		if !p.IsGoalie() {
+			return 0
+		}
+		return float64(p.Started)
+	}},
+	yahoo.StatWins: {"W", func(p sportsData.Player) float64 { return p.GoaltendingWins }},
+	// Yahoo counts overtime and shootout losses as losses
+	20:                     {"L", func(p sportsData.Player) float64 { return p.GoaltendingLosses + p.GoaltendingOvertimeLosses }},
+	yahoo.StatGoalsAgainst: {"GA", func(p sportsData.Player) float64 { return p.GoaltendingGoalsAgainst }},
+	yahoo.StatShotsAgainst: {"SA", func(p sportsData.Player) float64 { return p.GoaltendingShotsAgainst }},
+	yahoo.StatSaves:        {"SV", func(p sportsData.Player) float64 { return p.GoaltendingSaves }},
+	yahoo.StatShutouts:     {"SHO", func(p sportsData.Player) float64 { return p.GoaltendingShutouts }},
+}
+
+// Line is the points one stat contributes.
+type Line struct {
+	StatID   int     `json:"stat_id"`
+	Name     string  `json:"name"`
+	Value    float64 `json:"value"`
+	Modifier float64 `json:"modifier"`
+	Points   float64 `json:"points"`
+}
+
+// Score is a stat line's fantasy points in a league and how they add up.
+type Score struct {
+	Points float64 `json:"points"`
+	Lines  []Line  `json:"lines"`
+	// Unsupported lists the league's scored stats a SportsData.io stat
+	// line has no value for, such as faceoffs won, which count as 0
+	Unsupported []int `json:"unsupported,omitempty"`
+}
+
+// Points scores a projected or actual stat line with the league's stat
+// modifiers, for skaters and goalies alike.
+func Points(settings yahoo.LeagueSettings, p sportsData.Player) (Score, error) {
+	modifiers := settings.StatModifiers.Stats.Stat
+	if len(modifiers) == 0 {
+		return Score{}, ErrNoModifiers
+	}
+
+	var score Score
+	for _, m := range modifiers {
+		st, ok := stats[m.StatID]
+		if !ok {
+			score.Unsupported = append(score.Unsupported, m.StatID)
+			continue
+		}
+		v := st.value(p)
+		if v == 0 {
+			continue
+		}
+		line := Line{StatID: m.StatID, Name: st.name, Value: v, Modifier: m.Value, Points: v * m.Value}
+		if c, ok := settings.Category(m.StatID); ok {
+			line.Name = c.DisplayName
+		}
+		score.Lines = append(score.Lines, line)
+		score.Points += line.Points
+	}
+	return score, nil
+}
+
+// Ranked is a stat line with its league points.
+type Ranked struct {
+	Player sportsData.Player `json:"player"`
+	Score  Score             `json:"score"`
+}
+
+// Rank scores every stat line and returns them with the most points first.
+func Rank(settings yahoo.LeagueSettings, lines map[int]sportsData.Player) ([]Ranked, error) {
+	var ranked []Ranked
+	for _, p := range lines {
+		score, err := Points(settings, p)
+		if err != nil {
+			return nil, err
+		}
+		ranked = append(ranked, Ranked{Player: p, Score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score.Points != ranked[j].Score.Points {
+			return ranked[i].Score.Points > ranked[j].Score.Points
+		}
+		return ranked[i].Player.Name < ranked[j].Player.Name
+	})
+	return ranked, nil
+}
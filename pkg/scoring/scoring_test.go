@@ -0,0 +1,90 @@
+package scoring_test
+
+import (
+	"errors"
+	"hockey-hacks/pkg/scoring"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// pointsSettings scores the stats with the modifiers, in order.
+func pointsSettings(modifiers ...yahoo.StatModifier) yahoo.LeagueSettings {
+	var s yahoo.LeagueSettings
+	s.StatModifiers.Stats.Stat = modifiers
+	return s
+}
+
+func TestPoints(t *testing.T) {
+	settings := pointsSettings(
+		yahoo.StatModifier{StatID: 1, Value: 3},                      // G
+		yahoo.StatModifier{StatID: 2, Value: 2},                      // A
+		yahoo.StatModifier{StatID: 8, Value: 1},                      // PPP
+		yahoo.StatModifier{StatID: 14, Value: 0.5},                   // SOG
+		yahoo.StatModifier{StatID: yahoo.StatGoalieStarts, Value: 1}, // GS
+		yahoo.StatModifier{StatID: yahoo.StatWins, Value: 4},
+		yahoo.StatModifier{StatID: 20, Value: -1}, // L
+		yahoo.StatModifier{StatID: yahoo.StatGoalsAgainst, Value: -1},
+		yahoo.StatModifier{StatID: yahoo.StatSaves, Value: 0.2},
+		yahoo.StatModifier{StatID: 34, Value: 0.5}, // faceoffs won
+	)
+	tests := []struct {
+		name   string
+		player sportsData.Player
+		points float64
+		lines  int
+	}{
+		{
+			name:   "skater",
+			player: sportsData.Player{Position: "C", Goals: 1, Assists: 2, PowerPlayGoals: 1, ShotsOnGoal: 4},
+			// 3 + 4 + 1 + 2
+			points: 10,
+			lines:  4,
+		},
+		{
+			name:   "projected skater",
+			player: sportsData.Player{Position: "D", Goals: 0.2, Assists: 0.5, ShotsOnGoal: 2.4},
+			// 0.6 + 1 + 1.2
+			points: 2.8,
+			lines:  3,
+		},
+		{
+			name:   "skater starts do not count as goalie starts",
+			player: sportsData.Player{Position: "LW", Started: 1},
+			lines:  0,
+		},
+		{
+			name: "goalie",
+			player: sportsData.Player{Position: "G", Started: 1, GoaltendingGoalsAgainst: 3, GoaltendingSaves: 30,
+				GoaltendingLosses: 0, GoaltendingOvertimeLosses: 1},
+			// 1 - 1 - 3 + 6
+			points: 3,
+			lines:  4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, err := scoring.Points(settings, tt.player)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(score.Points-tt.points) > 1e-9 {
+				t.Errorf("points = %v, want %v (%+v)", score.Points, tt.points, score.Lines)
+			}
+			if len(score.Lines) != tt.lines {
+				t.Errorf("got %d lines, want %d: %+v", len(score.Lines), tt.lines, score.Lines)
+			}
+			if !reflect.DeepEqual(score.Unsupported, []int{34}) {
+				t.Errorf("unsupported = %v, want [34]", score.Unsupported)
+			}
+		})
+	}
+}
+
+func TestPointsNoModifiers(t *testing.T) {
+	if _, err := scoring.Points(yahoo.LeagueSettings{}, sportsData.Player{Goals: 1}); !errors.Is(err, scoring.ErrNoModifiers) {
+		t.Errorf("Points() = %v, want ErrNoModifiers", err)
+	}
+}
@@ -0,0 +1,215 @@
+package sportsData
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hockey-hacks/pkg/clock"
+	"hockey-hacks/pkg/config"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultCacheDir = config.RootDir + "/sportsdata_cache"
+
+	// usageFile records the API calls made today, next to the cached
+	// responses
+	usageFile = "usage.json"
+
+	// pastTTL is how long responses for past dates are kept, since their
+	// data no longer changes
+	pastTTL = 30 * 24 * time.Hour
+)
+
+// ErrDailyLimit is returned instead of calling the API once the day's call
+// limit is used up and no cached response is left.
+var ErrDailyLimit = errors.New("sports data daily api call limit reached")
+
+// TTLs are how long responses are cached, by the endpoint they came from.
+// Endpoints not listed are not cached.
+var TTLs = map[string]time.Duration{
+	"StartingGoaltendersByDate":       15 * time.Minute,
+	"PlayerGameStatsByDate":           15 * time.Minute,
+	"PlayerGameProjectionStatsByDate": time.Hour,
+	"GamesByDate":                     6 * time.Hour,
+	"Games":                           24 * time.Hour,
+	"Stadiums":                        7 * 24 * time.Hour,
+}
+
+// CacheDir returns the cache directory from SPORTS_DATA_CACHE_DIR or the
+// default. "off" disables the cache.
+func CacheDir() string {
+	if dir := os.Getenv("SPORTS_DATA_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	return DefaultCacheDir
+}
+
+// DailyLimit returns the most API calls allowed per day from
+// SPORTS_DATA_DAILY_LIMIT, 0 for no limit.
+func DailyLimit() int {
+	limit, _ := strconv.Atoi(os.Getenv("SPORTS_DATA_DAILY_LIMIT"))
+	return limit
+}
+
+// Cache keeps API responses on disk so they survive between runs, and
+// counts the API calls made each day against a hard limit.
+type Cache struct {
+	Dir string
+	// Limit is the most API calls per day, 0 for no limit
+	Limit int
+	Clock clock.Clock
+
+	mu sync.Mutex
+}
+
+// NewCache returns a cache in the directory, or nil when dir is "off".
+func NewCache(dir string, limit int) *Cache {
+	if dir == "off" {
+		return nil
+	}
+	return &Cache{Dir: dir, Limit: limit, Clock: clock.Real{}}
+}
+
+type cachedResponse struct {
+	Fetched time.Time       `json:"fetched"`
+	Body    json.RawMessage `json:"body"`
+}
+
+type usage struct {
+	Date  string `json:"date"`
+	Calls int    `json:"calls"`
+}
+
+// endpoint returns the endpoint name and date, if any, of a request path
+// such as /projections/json/StartingGoaltendersByDate/2024-10-19.
+func endpoint(path string) (string, time.Time, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) >= 2 {
+		last := parts[len(parts)-1]
+		for _, layout := range []string{time.DateOnly, "2006-Jan-02"} {
+			if date, err := time.Parse(layout, last); err == nil {
+				return parts[len(parts)-2], date, true
+			}
+		}
+	}
+	if len(parts) >= 2 && parts[len(parts)-2] == "Games" {
+		return "Games", time.Time{}, false
+	}
+	return parts[len(parts)-1], time.Time{}, false
+}
+
+// ttl returns how long the response for the path stays fresh, 0 when it is
+// not cached.
+func (c *Cache) ttl(path string) time.Duration {
+	name, date, dated := endpoint(path)
+	ttl, ok := TTLs[name]
+	if !ok {
+		return 0
+	}
+	if dated && date.Format(time.DateOnly) < clock.Today(c.Clock).Format(time.DateOnly) {
+		return pastTTL
+	}
+	return ttl
+}
+
+func (c *Cache) file(path string) string {
+	key := strings.NewReplacer("/", "_", ":", "_", "?", "_").Replace(strings.Trim(path, "/"))
+	return filepath.Join(c.Dir, key+".json")
+}
+
+// Get returns the cached response for the path. Stale responses are
+// returned too, marked as not fresh, for use when the API cannot be called.
+func (c *Cache) Get(path string) (body []byte, fresh bool, ok bool) {
+	ttl := c.ttl(path)
+	if ttl == 0 {
+		return nil, false, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(c.file(path))
+	if err != nil {
+		return nil, false, false
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, false, false
+	}
+	return cached.Body, c.Clock.Now().Sub(cached.Fetched) < ttl, true
+}
+
+// Put stores a successful response for the path, when its endpoint is
+// cached.
+func (c *Cache) Put(path string, body []byte) error {
+	if c.ttl(path) == 0 || !json.Valid(body) {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(cachedResponse{Fetched: c.Clock.Now(), Body: body})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(c.file(path), data, 0600)
+}
+
+// Count records an API call, refusing it with ErrDailyLimit once the day's
+// limit is reached. It returns the number of calls made today.
+func (c *Cache) Count() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	u, err := c.usage()
+	if err != nil {
+		return 0, err
+	}
+	if c.Limit > 0 && u.Calls >= c.Limit {
+		return u.Calls, fmt.Errorf("%w: %d calls on %s", ErrDailyLimit, u.Calls, u.Date)
+	}
+	u.Calls++
+	data, err := json.Marshal(u)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return 0, err
+	}
+	return u.Calls, os.WriteFile(filepath.Join(c.Dir, usageFile), data, 0600)
+}
+
+// Calls returns the number of API calls made today.
+func (c *Cache) Calls() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	u, err := c.usage()
+	return u.Calls, err
+}
+
+func (c *Cache) usage() (usage, error) {
+	today := clock.Today(c.Clock).Format(time.DateOnly)
+	u := usage{Date: today}
+	data, err := os.ReadFile(filepath.Join(c.Dir, usageFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return u, nil
+	} else if err != nil {
+		return u, err
+	}
+	if err := json.Unmarshal(data, &u); err != nil {
+		return u, err
+	}
+	if u.Date != today {
+		u = usage{Date: today}
+	}
+	return u, nil
+}
@@ -0,0 +1,118 @@
+package sportsData_test
+
+import (
+	"errors"
+	"hockey-hacks/pkg/fakeServer"
+	"hockey-hacks/pkg/sportsData"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// stepClock is a clock tests move forward.
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	return c.now
+}
+
+var cacheNow = time.Date(2024, 10, 19, 14, 0, 0, 0, time.UTC)
+
+func TestCacheTTL(t *testing.T) {
+	clk := &stepClock{now: cacheNow}
+	c := &sportsData.Cache{Dir: t.TempDir(), Clock: clk}
+	body := []byte(`[{"GameID":1}]`)
+
+	tests := []struct {
+		path  string
+		after time.Duration
+		fresh bool
+	}{
+		{"/projections/json/StartingGoaltendersByDate/2024-10-19", 14 * time.Minute, true},
+		{"/projections/json/StartingGoaltendersByDate/2024-10-19", 16 * time.Minute, false},
+		{"/scores/json/GamesByDate/2024-10-19", 5 * time.Hour, true},
+		{"/scores/json/GamesByDate/2024-10-19", 7 * time.Hour, false},
+		// Past dates no longer change and are kept for 30 days
+		{"/projections/json/StartingGoaltendersByDate/2024-10-18", 29 * 24 * time.Hour, true},
+		{"/scores/json/Stadiums", 6 * 24 * time.Hour, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path+" after "+tt.after.String(), func(t *testing.T) {
+			clk.now = cacheNow
+			if err := c.Put(tt.path, body); err != nil {
+				t.Fatal(err)
+			}
+			clk.now = cacheNow.Add(tt.after)
+			got, fresh, ok := c.Get(tt.path)
+			if !ok || string(got) != string(body) {
+				t.Fatalf("Get() = %s, %t, want the cached body", got, ok)
+			}
+			if fresh != tt.fresh {
+				t.Errorf("fresh = %t, want %t", fresh, tt.fresh)
+			}
+		})
+	}
+
+	if err := c.Put("/scores/json/Teams", body); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := c.Get("/scores/json/Teams"); ok {
+		t.Error("cached an endpoint without a TTL")
+	}
+}
+
+func TestCacheDailyLimit(t *testing.T) {
+	clk := &stepClock{now: cacheNow}
+	c := &sportsData.Cache{Dir: t.TempDir(), Limit: 2, Clock: clk}
+
+	for want := 1; want <= 2; want++ {
+		if calls, err := c.Count(); err != nil || calls != want {
+			t.Fatalf("Count() = %d, %v, want %d", calls, err, want)
+		}
+	}
+	if _, err := c.Count(); !errors.Is(err, sportsData.ErrDailyLimit) {
+		t.Fatalf("Count() over the limit = %v, want ErrDailyLimit", err)
+	}
+
+	clk.now = cacheNow.Add(24 * time.Hour)
+	if calls, err := c.Count(); err != nil || calls != 1 {
+		t.Errorf("Count() the next day = %d, %v, want 1", calls, err)
+	}
+}
+
+func TestClientFallsBackToStaleCacheAtLimit(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	clk := &stepClock{now: cacheNow}
+	sd := s.SportsDataClient()
+	sd.Cache = &sportsData.Cache{Dir: t.TempDir(), Limit: 1, Clock: clk}
+	date := time.Date(2024, 10, 19, 0, 0, 0, 0, time.UTC)
+	calls := func() int {
+		return len(s.RequestsTo(http.MethodGet, "/StartingGoaltendersByDate/"))
+	}
+
+	first, err := sd.GetStartingGoalies(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sd.GetStartingGoalies(date); err != nil || calls() != 1 {
+		t.Fatalf("fresh cache: %v, %d API calls, want 1", err, calls())
+	}
+
+	// Stale now, but the day's only call is used up
+	clk.now = cacheNow.Add(time.Hour)
+	stale, err := sd.GetStartingGoalies(date)
+	if err != nil {
+		t.Fatalf("stale fallback: %v", err)
+	}
+	if calls() != 1 || len(stale) != len(first) {
+		t.Errorf("stale fallback made %d API calls and returned %d games, want 1 and %d", calls(), len(stale), len(first))
+	}
+
+	// Nothing cached for another date
+	if _, err := sd.GetStartingGoalies(date.AddDate(0, 0, 1)); !errors.Is(err, sportsData.ErrDailyLimit) {
+		t.Errorf("uncached request at the limit = %v, want ErrDailyLimit", err)
+	}
+}
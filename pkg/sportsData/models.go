@@ -1,8 +1,21 @@
 // File: sportsData/models.go
 package sportsData
 
+// Player is a player's stat line for one game, projected or actual.
+// Counting stats in projections are expected values, so they are
+// fractional.
 type Player struct {
+	PlayerID           int     `json:"PlayerID"`
 	Name               string  `json:"Name"`
+	Team               string  `json:"Team"`
+	Position           string  `json:"Position"`
+	GameID             int     `json:"GameID"`
+	Day                string  `json:"Day"`
+	DateTime           string  `json:"DateTime"`
+	Opponent           string  `json:"Opponent"`
+	HomeOrAway         string  `json:"HomeOrAway"`
+	Started            int     `json:"Started"`
+	FantasyPoints      float64 `json:"FantasyPoints"`
 	FantasyPointsYahoo float64 `json:"FantasyPointsYahoo"`
 	ShotsOnGoal        float64 `json:"ShotsOnGoal"`
 	PowerPlayGoals     float64 `json:"PowerPlayGoals"`
@@ -16,6 +29,16 @@ type Player struct {
 	Hits               float64 `json:"Hits"`
 	Goals              float64 `json:"Goals"`
 	Assists            float64 `json:"Assists"`
+
+	// Goalie stats. In projections GoaltendingWins is the chance of a win.
+	GoaltendingMinutes        float64 `json:"GoaltendingMinutes"`
+	GoaltendingSaves          float64 `json:"GoaltendingSaves"`
+	GoaltendingShotsAgainst   float64 `json:"GoaltendingShotsAgainst"`
+	GoaltendingGoalsAgainst   float64 `json:"GoaltendingGoalsAgainst"`
+	GoaltendingWins           float64 `json:"GoaltendingWins"`
+	GoaltendingLosses         float64 `json:"GoaltendingLosses"`
+	GoaltendingOvertimeLosses float64 `json:"GoaltendingOvertimeLosses"`
+	GoaltendingShutouts       float64 `json:"GoaltendingShutouts"`
 }
 
 type Goalie struct {
@@ -30,6 +53,21 @@ type Goalie struct {
 type Goalies []Goalie
 
 type Game struct {
+	GameID     int    `json:"GameID"`
+	Season     int    `json:"Season"`
+	SeasonType int    `json:"SeasonType"`
+	Status     string `json:"Status"`
+	// Day is the game's date and DateTime its start, both in US Eastern
+	// time; DateTimeUTC is the start in UTC. See Start.
+	Day         string `json:"Day"`
+	DateTime    string `json:"DateTime"`
+	DateTimeUTC string `json:"DateTimeUTC"`
+	StadiumID   int    `json:"StadiumID"`
+	// Venue is the name of the arena, looked up from StadiumID when the
+	// schedule is fetched
+	Venue          string `json:"-"`
+	Channel        string `json:"Channel"`
+	IsClosed       bool   `json:"IsClosed"`
 	HomeTeamID     int    `json:"HomeTeamID"`
 	HomeTeam       string `json:"HomeTeam"`
 	AwayTeamID     int    `json:"AwayTeamID"`
@@ -39,3 +77,12 @@ type Game struct {
 }
 
 type Games []Game
+
+type Stadium struct {
+	StadiumID int    `json:"StadiumID"`
+	Name      string `json:"Name"`
+	City      string `json:"City"`
+	State     string `json:"State"`
+	Country   string `json:"Country"`
+	Capacity  int    `json:"Capacity"`
+}
@@ -0,0 +1,77 @@
+package sportsData
+
+import (
+	"encoding/json"
+	"hockey-hacks/pkg/email"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Projections are the projected game stat lines of a day, keyed by
+// SportsData.io player ID.
+type Projections map[int]Player
+
+// GetPlayerProjections returns every player's projected stats for the games
+// on the given date.
+func (c *Client) GetPlayerProjections(date time.Time) (Projections, error) {
+	lines, err := c.getStatLines("/projections/json/PlayerGameProjectionStatsByDate/", date)
+	return Projections(lines), err
+}
+
+// GetPlayerGameStats returns every player's actual stats for the games on
+// the given date, keyed by SportsData.io player ID.
+func (c *Client) GetPlayerGameStats(date time.Time) (map[int]Player, error) {
+	return c.getStatLines("/stats/json/PlayerGameStatsByDate/", date)
+}
+
+func (c *Client) getStatLines(endpoint string, date time.Time) (map[int]Player, error) {
+	day := strings.ToUpper(date.Format("2006-Jan-02"))
+	respBody, err := c.sendRequest(http.MethodGet, c.BaseURL+endpoint+day, nil)
+	if err != nil {
+		email.Notify(c.EnableEmail, respBody)
+		log.Println("Failed to get player stats:", err)
+		return nil, err
+	}
+	var players []Player
+	if err := json.Unmarshal(respBody, &players); err != nil {
+		return nil, err
+	}
+	lines := make(map[int]Player, len(players))
+	for _, p := range players {
+		lines[p.PlayerID] = p
+	}
+	return lines, nil
+}
+
+// IsGoalie reports whether the stat line is a goalie's.
+func (p Player) IsGoalie() bool {
+	return p.Position == "G"
+}
+
+// FirstLast splits the player's name into first and last name.
+func (p Player) FirstLast() (string, string) {
+	first, last, found := strings.Cut(p.Name, " ")
+	if !found {
+		return "", p.Name
+	}
+	return first, last
+}
+
+// WinProbability returns the goalie's projected chance of a win.
+func (p Player) WinProbability() float64 {
+	if p.GoaltendingWins > 1 {
+		return 1
+	}
+	return p.GoaltendingWins
+}
+
+// SavePercentage returns the goalie's projected save percentage, or 0
+// without shots against.
+func (p Player) SavePercentage() float64 {
+	if p.GoaltendingShotsAgainst == 0 {
+		return 0
+	}
+	return p.GoaltendingSaves / p.GoaltendingShotsAgainst
+}
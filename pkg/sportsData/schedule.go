@@ -0,0 +1,234 @@
+package sportsData
+
+import (
+	"encoding/json"
+	"fmt"
+	"hockey-hacks/pkg/email"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Game statuses
+	StatusScheduled  = "Scheduled"
+	StatusInProgress = "InProgress"
+	StatusFinal      = "Final"
+	StatusFinalOT    = "F/OT"
+	StatusFinalSO    = "F/SO"
+	StatusPostponed  = "Postponed"
+	StatusCanceled   = "Canceled"
+	StatusSuspended  = "Suspended"
+
+	// dateTimeLayout is how SportsData.io formats game times, without a zone
+	dateTimeLayout = "2006-01-02T15:04:05"
+)
+
+// eastern is the time zone SportsData.io reports local game times in.
+var eastern = func() *time.Location {
+	if loc, err := time.LoadLocation("America/New_York"); err == nil {
+		return loc
+	}
+	return time.FixedZone("EST", -5*60*60)
+}()
+
+// GetGames returns the NHL games scheduled on the given date.
+func (c *Client) GetGames(date time.Time) (Games, error) {
+	day := strings.ToUpper(date.Format("2006-Jan-02"))
+	return c.getGames(c.BaseURL + "/scores/json/GamesByDate/" + day)
+}
+
+// GetSeasonGames returns every game of the season, e.g. "2025" for the
+// 2024-25 regular season or "2025POST" for its playoffs.
+func (c *Client) GetSeasonGames(season string) (Games, error) {
+	return c.getGames(c.BaseURL + "/scores/json/Games/" + season)
+}
+
+// GetGamesBetween returns the games from one date to another, inclusive,
+// fetching the schedule of each season the range touches once.
+func (c *Client) GetGamesBetween(from time.Time, to time.Time) (Games, error) {
+	var games Games
+	for season := Season(from); season <= Season(to); season++ {
+		seasonGames, err := c.GetSeasonGames(fmt.Sprint(season))
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, seasonGames.Between(from, to)...)
+	}
+	return games, nil
+}
+
+// TeamGame returns the team's game on the date, if it plays.
+func (c *Client) TeamGame(team string, date time.Time) (Game, bool, error) {
+	games, err := c.GetGames(date)
+	if err != nil {
+		return Game{}, false, err
+	}
+	g, ok := games.TeamGame(team)
+	return g, ok, nil
+}
+
+// GetStadiums returns the NHL venues by stadium ID.
+func (c *Client) GetStadiums() (map[int]Stadium, error) {
+	respBody, err := c.sendRequest(http.MethodGet, c.BaseURL+"/scores/json/Stadiums", nil)
+	if err != nil {
+		email.Notify(c.EnableEmail, respBody)
+		log.Println("Failed to get stadiums:", err)
+		return nil, err
+	}
+	var stadiums []Stadium
+	if err := json.Unmarshal(respBody, &stadiums); err != nil {
+		return nil, err
+	}
+	byID := make(map[int]Stadium, len(stadiums))
+	for _, s := range stadiums {
+		byID[s.StadiumID] = s
+	}
+	return byID, nil
+}
+
+func (c *Client) getGames(url string) (Games, error) {
+	respBody, err := c.sendRequest(http.MethodGet, url, nil)
+	if err != nil {
+		email.Notify(c.EnableEmail, respBody)
+		log.Println("Failed to get schedule:", err)
+		return nil, err
+	}
+	var games Games
+	if err := json.Unmarshal(respBody, &games); err != nil {
+		return nil, err
+	}
+	c.addVenues(games)
+	return games, nil
+}
+
+// addVenues names each game's arena, fetching the stadiums once per client.
+// Venues stay empty when the stadiums cannot be fetched, since the schedule
+// is still usable without them.
+func (c *Client) addVenues(games Games) {
+	c.venuesMu.Lock()
+	defer c.venuesMu.Unlock()
+	if c.venues == nil {
+		stadiums, err := c.GetStadiums()
+		if err != nil {
+			return
+		}
+		c.venues = make(map[int]string, len(stadiums))
+		for id, s := range stadiums {
+			c.venues[id] = s.Name
+		}
+	}
+	for i := range games {
+		games[i].Venue = c.venues[games[i].StadiumID]
+	}
+}
+
+// Season returns the season a date falls in, named after the year it ends.
+// Seasons are taken to start in August.
+func Season(date time.Time) int {
+	if date.Month() >= time.August {
+		return date.Year() + 1
+	}
+	return date.Year()
+}
+
+// Start returns when the game starts, if the schedule has a time for it.
+func (g Game) Start() (time.Time, bool) {
+	if t, err := time.ParseInLocation(dateTimeLayout, g.DateTimeUTC, time.UTC); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation(dateTimeLayout, g.DateTime, eastern); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// Date returns the game's date in US Eastern time.
+func (g Game) Date() (time.Time, bool) {
+	day := g.Day
+	if day == "" {
+		day = g.DateTime
+	}
+	if len(day) < len(time.DateOnly) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(time.DateOnly, day[:len(time.DateOnly)], eastern)
+	return t, err == nil
+}
+
+// Started reports whether the game has started or is off the schedule by
+// the given time.
+func (g Game) Started(now time.Time) bool {
+	switch g.Status {
+	case StatusScheduled, "":
+		start, ok := g.Start()
+		return ok && !now.Before(start)
+	}
+	return true
+}
+
+// Cancelled reports whether the game will not be played as scheduled.
+func (g Game) Cancelled() bool {
+	return g.Status == StatusPostponed || g.Status == StatusCanceled
+}
+
+// Plays reports whether the team is one of the game's.
+func (g Game) Plays(team string) bool {
+	team = TeamAbbr(team)
+	return g.HomeTeam == team || g.AwayTeam == team
+}
+
+// TeamGame returns the team's game, if it plays in one of the games.
+// Postponed and cancelled games are skipped.
+func (gs Games) TeamGame(team string) (Game, bool) {
+	for _, g := range gs {
+		if g.Plays(team) && !g.Cancelled() {
+			return g, true
+		}
+	}
+	return Game{}, false
+}
+
+// FirstStart returns the start of the earliest game, if any has a time.
+func (gs Games) FirstStart() (time.Time, bool) {
+	var first time.Time
+	found := false
+	for _, g := range gs {
+		if g.Cancelled() {
+			continue
+		}
+		if start, ok := g.Start(); ok && (!found || start.Before(first)) {
+			first, found = start, true
+		}
+	}
+	return first, found
+}
+
+// NotStarted returns the games that have not started by the given time.
+func (gs Games) NotStarted(now time.Time) Games {
+	var games Games
+	for _, g := range gs {
+		if !g.Started(now) {
+			games = append(games, g)
+		}
+	}
+	return games
+}
+
+// Between returns the games played from one date to another, inclusive.
+func (gs Games) Between(from time.Time, to time.Time) Games {
+	first := from.Format(time.DateOnly)
+	last := to.Format(time.DateOnly)
+	var games Games
+	for _, g := range gs {
+		d, ok := g.Date()
+		if !ok {
+			continue
+		}
+		if day := d.Format(time.DateOnly); day >= first && day <= last {
+			games = append(games, g)
+		}
+	}
+	return games
+}
@@ -0,0 +1,50 @@
+package sportsData_test
+
+import (
+	"hockey-hacks/pkg/fakeServer"
+	"net/http"
+	"testing"
+)
+
+func TestGetGamesNamesVenues(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	c := s.SportsDataClient()
+
+	for i := 0; i < 2; i++ {
+		games, err := c.GetGames(cacheNow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		g, ok := games.TeamGame("TOR")
+		if !ok {
+			t.Fatal("no TOR game")
+		}
+		if g.Venue != "Scotiabank Arena" {
+			t.Errorf("venue = %q, want Scotiabank Arena", g.Venue)
+		}
+	}
+	if n := len(s.RequestsTo(http.MethodGet, "/Stadiums")); n != 1 {
+		t.Errorf("got %d stadium requests, want 1", n)
+	}
+}
+
+func TestGetGamesWithoutStadiums(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	s.Handle(http.MethodGet, "/Stadiums", fakeServer.Response{Status: http.StatusInternalServerError})
+	c := s.SportsDataClient()
+
+	games, err := c.GetGames(cacheNow)
+	if err != nil {
+		t.Fatalf("schedule failed without stadiums: %v", err)
+	}
+	if len(games) == 0 {
+		t.Fatal("got no games")
+	}
+	for _, g := range games {
+		if g.Venue != "" {
+			t.Errorf("game %d venue = %q, want none", g.GameID, g.Venue)
+		}
+	}
+}
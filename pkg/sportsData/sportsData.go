@@ -2,11 +2,15 @@ package sportsData
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"hockey-hacks/pkg/email"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,46 +18,111 @@ const (
 	SportsDataAPIBaseURL = "https://api.sportsdata.io/v3/nhl"
 )
 
-func GetStartingGoalies() (Games, error) {
-	date := time.Now().Format("2006-01-02")
-	sportsDataUrl := SportsDataAPIBaseURL + "/projections/json/StartingGoaltendersByDate/" + date
+type Client struct {
+	APIKey      string
+	EnableEmail bool
+	// Cache serves repeated requests from disk and enforces the daily call
+	// limit, nil to always call the API
+	Cache *Cache
 
-	respBody, err := sendRequest(http.MethodGet, sportsDataUrl, nil)
+	// HTTPClient and BaseURL can be replaced to talk to a fake server in
+	// tests.
+	HTTPClient *http.Client
+	BaseURL    string
+
+	// venues caches the stadium names by ID for the client's lifetime
+	venuesMu sync.Mutex
+	venues   map[int]string
+}
+
+func NewClient(enableEmail bool) *Client {
+	return &Client{
+		APIKey:      os.Getenv("SPORTS_DATA_KEY"),
+		EnableEmail: enableEmail,
+		Cache:       NewCache(CacheDir(), DailyLimit()),
+		HTTPClient:  &http.Client{},
+		BaseURL:     SportsDataAPIBaseURL,
+	}
+}
+
+// GetStartingGoalies returns the games on the given date with their
+// projected starting goalies.
+func (c *Client) GetStartingGoalies(date time.Time) (Games, error) {
+	sportsDataUrl := c.BaseURL + "/projections/json/StartingGoaltendersByDate/" + date.Format(time.DateOnly)
+
+	respBody, err := c.sendRequest(http.MethodGet, sportsDataUrl, nil)
 
 	if err != nil {
-		email.SendEmail(respBody)
-		log.Fatalln("Failed to get starting goalies")
+		email.Notify(c.EnableEmail, respBody)
+		log.Println("Failed to get starting goalies:", err)
 		return nil, err
 	}
 	var games Games
-	json.Unmarshal([]byte(respBody), &games)
+	if err := json.Unmarshal(respBody, &games); err != nil {
+		return nil, err
+	}
 
 	return games, nil
 }
 
-func sendRequest(method string, url string, body io.Reader) ([]byte, error) {
-	client := http.Client{}
-	req, err := http.NewRequest(method, url, nil)
+// sendRequest answers GET requests from the cache while they are fresh,
+// and otherwise calls the API as long as the daily limit allows, falling
+// back to a stale cached response once it is used up.
+func (c *Client) sendRequest(method string, url string, body io.Reader) ([]byte, error) {
+	path := strings.TrimPrefix(url, c.BaseURL)
+	cacheable := c.Cache != nil && method == http.MethodGet
+	if cacheable {
+		if cached, fresh, ok := c.Cache.Get(path); ok && fresh {
+			return cached, nil
+		}
+	}
+	if c.Cache != nil {
+		calls, err := c.Cache.Count()
+		switch {
+		case errors.Is(err, ErrDailyLimit):
+			if cached, _, ok := c.Cache.Get(path); ok && cacheable {
+				log.Printf("Using stale cached %s: %v", path, err)
+				return cached, nil
+			}
+			return nil, err
+		case err != nil:
+			log.Println("Failed to count sports data api call:", err)
+		case c.Cache.Limit > 0:
+			log.Printf("Sports data api call %d of %d today: %s", calls, c.Cache.Limit, path)
+		default:
+			log.Printf("Sports data api call %d today: %s", calls, path)
+		}
+	}
+
+	respBody, err := c.do(method, url, body)
+	if err == nil && cacheable {
+		if err := c.Cache.Put(path, respBody); err != nil {
+			log.Println("Failed to cache sports data response:", err)
+		}
+	}
+	return respBody, err
+}
+
+func (c *Client) do(method string, url string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, url, body)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 	req.Header = http.Header{
 		"Content-Type":              {"application/json"},
-		"Ocp-Apim-Subscription-Key": {os.Getenv("SPORTS_DATA_KEY")},
+		"Ocp-Apim-Subscription-Key": {c.APIKey},
 	}
-	resp, err := client.Do(req)
+	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
+	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
-
 	if err != nil {
-		log.Fatalln(err)
 		return nil, err
-	} else if resp.StatusCode != 200 {
-		log.Fatalln("Sports Data API Error")
-		return respBody, err
+	} else if resp.StatusCode != http.StatusOK {
+		return respBody, fmt.Errorf("sports data api error: status %d", resp.StatusCode)
 	}
 	return respBody, nil
 }
@@ -0,0 +1,40 @@
+package sportsData
+
+import "strings"
+
+// teamAliases maps abbreviations used by other providers (Yahoo, NHL.com)
+// to the SportsData.io abbreviation where the two differ.
+var teamAliases = map[string]string{
+	"CBJ": "CLB",
+	"LAK": "LA",
+	"MTL": "MON",
+	"NJD": "NJ",
+	"NSH": "NAS",
+	"SJS": "SJ",
+	"TBL": "TB",
+	"VGK": "VEG",
+	"WSH": "WAS",
+}
+
+// TeamAbbr normalizes an NHL team abbreviation to the SportsData.io format.
+func TeamAbbr(abbr string) string {
+	abbr = strings.ToUpper(strings.TrimSpace(abbr))
+	if alias, ok := teamAliases[abbr]; ok {
+		return alias
+	}
+	return abbr
+}
+
+// Teams returns the set of teams playing in the given games, leaving out
+// postponed and cancelled games.
+func (gs Games) Teams() map[string]bool {
+	teams := make(map[string]bool)
+	for _, g := range gs {
+		if g.Cancelled() {
+			continue
+		}
+		teams[g.HomeTeam] = true
+		teams[g.AwayTeam] = true
+	}
+	return teams
+}
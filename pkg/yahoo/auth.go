@@ -0,0 +1,207 @@
+package yahoo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hockey-hacks/pkg/config"
+	"hockey-hacks/pkg/email"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTokenPath = config.RootDir + "/yahoo_token.json"
+
+	// Tokens this close to expiry are refreshed before use
+	tokenExpiryMargin = 5 * time.Minute
+)
+
+// TokenStore persists the Yahoo token between runs.
+type TokenStore interface {
+	// Load returns the stored token, or nil when there is none.
+	Load() (*YahooAuth, error)
+	Save(auth YahooAuth) error
+}
+
+// TokenPath returns the token file location from YAHOO_TOKEN_PATH or the
+// default.
+func TokenPath() string {
+	if path := os.Getenv("YAHOO_TOKEN_PATH"); path != "" {
+		return path
+	}
+	return DefaultTokenPath
+}
+
+// FileTokenStore keeps the token in a JSON file.
+type FileTokenStore struct {
+	Path string
+}
+
+func (s *FileTokenStore) Load() (*YahooAuth, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var auth YahooAuth
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return nil, err
+	}
+	return &auth, nil
+}
+
+func (s *FileTokenStore) Save(auth YahooAuth) error {
+	data, err := json.MarshalIndent(auth, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0600)
+}
+
+// MemoryTokenStore keeps the token in memory, for tests.
+type MemoryTokenStore struct {
+	mu   sync.Mutex
+	auth *YahooAuth
+}
+
+func (s *MemoryTokenStore) Load() (*YahooAuth, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.auth == nil {
+		return nil, nil
+	}
+	auth := *s.auth
+	return &auth, nil
+}
+
+func (s *MemoryTokenStore) Save(auth YahooAuth) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = &auth
+	return nil
+}
+
+// valid reports whether the access token can be used without refreshing.
+func (a YahooAuth) valid(now time.Time) bool {
+	return a.AccessToken != "" && now.Add(tokenExpiryMargin).Before(a.Expiry)
+}
+
+// Authenticate makes sure the client holds a usable access token, reusing
+// the stored one while it is valid and refreshing it otherwise.
+func (yc *YahooClient) Authenticate() error {
+	_, err := yc.accessToken()
+	return err
+}
+
+// accessToken returns a valid access token. Concurrent callers wait on a
+// single refresh rather than each refreshing.
+func (yc *YahooClient) accessToken() (string, error) {
+	yc.authMu.Lock()
+	defer yc.authMu.Unlock()
+
+	if yc.Auth.valid(yc.Clock.Now()) {
+		return yc.Auth.AccessToken, nil
+	}
+	if yc.TokenStore != nil && yc.Auth.AccessToken == "" {
+		stored, err := yc.TokenStore.Load()
+		if err != nil {
+			log.Println("Failed to load stored token:", err)
+		} else if stored != nil {
+			yc.Auth = *stored
+			if yc.Auth.valid(yc.Clock.Now()) {
+				return yc.Auth.AccessToken, nil
+			}
+		}
+	}
+	if err := yc.refresh(); err != nil {
+		return "", err
+	}
+	return yc.Auth.AccessToken, nil
+}
+
+// refreshStale refreshes the token after a 401, unless another caller has
+// already replaced the rejected token.
+func (yc *YahooClient) refreshStale(rejected string) error {
+	yc.authMu.Lock()
+	defer yc.authMu.Unlock()
+
+	if yc.Auth.AccessToken != rejected {
+		return nil
+	}
+	return yc.refresh()
+}
+
+// RefreshAuth exchanges the refresh token for a new access token even if
+// the current one is still valid.
+func (yc *YahooClient) RefreshAuth() error {
+	yc.authMu.Lock()
+	defer yc.authMu.Unlock()
+	return yc.refresh()
+}
+
+// refresh must be called with authMu held.
+func (yc *YahooClient) refresh() error {
+	tok := base64.StdEncoding.EncodeToString([]byte(os.Getenv("YAHOO_CLIENT_ID") + ":" + os.Getenv("YAHOO_CLIENT_SECRET")))
+
+	refreshToken := yc.Auth.RefreshToken
+	if refreshToken == "" {
+		refreshToken = os.Getenv("YAHOO_REFRESH_TOKEN")
+	}
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("redirect_uri", "oob")
+	data.Set("refresh_token", refreshToken)
+
+	req, err := http.NewRequest(http.MethodPost, yc.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header = http.Header{
+		"Authorization": {"Basic " + tok},
+		"Content-Type":  {"application/x-www-form-urlencoded"},
+	}
+	resp, err := yc.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		email.Notify(yc.EnableEmail, body)
+		log.Printf("Yahoo Auth Failed: %s", string(body))
+		return newTokenError(resp, body)
+	}
+
+	var auth YahooAuth
+	if err := json.Unmarshal(body, &auth); err != nil {
+		return err
+	}
+	// Yahoo only sometimes rotates the refresh token
+	if auth.RefreshToken == "" {
+		auth.RefreshToken = refreshToken
+	}
+	auth.Expiry = yc.Clock.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+	yc.Auth = auth
+
+	if yc.TokenStore != nil {
+		if err := yc.TokenStore.Save(auth); err != nil {
+			log.Println("Failed to save token:", err)
+		}
+	}
+	log.Printf("Refreshed Yahoo token, valid until %s", auth.Expiry.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,189 @@
+package yahoo_test
+
+import (
+	"errors"
+	"hockey-hacks/pkg/clock"
+	"hockey-hacks/pkg/fakeServer"
+	"hockey-hacks/pkg/yahoo"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRefreshAuthErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		kind   yahoo.ErrorKind
+	}{
+		{"revoked refresh token", http.StatusBadRequest, `{"error":"invalid_grant"}`, yahoo.ErrorAuthExpired},
+		{"expired refresh token", http.StatusUnauthorized, `{"error":"invalid_grant","error_description":"refresh token expired"}`, yahoo.ErrorAuthExpired},
+		{"bad client credentials", http.StatusUnauthorized, `{"error":"invalid_client"}`, yahoo.ErrorUnknown},
+		{"server error", http.StatusInternalServerError, `{"error":"server_error"}`, yahoo.ErrorUnknown},
+		{"throttled", 999, `Request denied`, yahoo.ErrorRateLimited},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := fakeServer.New()
+			defer s.Close()
+			s.Handle(http.MethodPost, "/oauth2/get_token", fakeServer.Response{
+				Status:      tt.status,
+				ContentType: "application/json",
+				Body:        []byte(tt.body),
+			})
+
+			err := s.YahooClient().RefreshAuth()
+			var apiErr *yahoo.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("RefreshAuth() = %v, want an APIError", err)
+			}
+			if apiErr.Kind != tt.kind {
+				t.Errorf("kind = %s, want %s (%v)", apiErr.Kind, tt.kind, err)
+			}
+		})
+	}
+}
+
+var authNow = time.Date(2024, 10, 19, 14, 0, 0, 0, time.UTC)
+
+func TestFileTokenStoreRoundTrip(t *testing.T) {
+	store := &yahoo.FileTokenStore{Path: filepath.Join(t.TempDir(), "yahoo_token.json")}
+	if auth, err := store.Load(); err != nil || auth != nil {
+		t.Fatalf("Load() of a missing file = %v, %v, want nil, nil", auth, err)
+	}
+
+	want := yahoo.YahooAuth{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresIn:    3600,
+		TokenType:    "bearer",
+		Expiry:       authNow.Add(time.Hour),
+	}
+	if err := store.Save(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || !reflect.DeepEqual(*got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+// storedClient returns a client whose token file holds the given token.
+func storedClient(t *testing.T, s *fakeServer.Server, auth yahoo.YahooAuth) (*yahoo.YahooClient, *yahoo.FileTokenStore) {
+	t.Helper()
+	store := &yahoo.FileTokenStore{Path: filepath.Join(t.TempDir(), "yahoo_token.json")}
+	if err := store.Save(auth); err != nil {
+		t.Fatal(err)
+	}
+	yc := s.YahooClient()
+	yc.TokenStore = store
+	yc.Clock = clock.Fixed(authNow)
+	return yc, store
+}
+
+func TestAuthenticateReusesUnexpiredToken(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	yc, _ := storedClient(t, s, yahoo.YahooAuth{
+		AccessToken:  "stored-access",
+		RefreshToken: "stored-refresh",
+		Expiry:       authNow.Add(time.Hour),
+	})
+
+	if err := yc.Authenticate(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(s.RequestsTo(http.MethodPost, "/oauth2/get_token")); n != 0 {
+		t.Errorf("got %d token requests, want the stored token reused", n)
+	}
+	if yc.Auth.AccessToken != "stored-access" {
+		t.Errorf("access token = %q, want stored-access", yc.Auth.AccessToken)
+	}
+}
+
+func TestAuthenticateRefreshesExpiredToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		wantRefresh string
+	}{
+		{"rotated refresh token", "", "fake-refresh-token"},
+		{"refresh token kept", `{"access_token":"new-access","expires_in":3600}`, "stored-refresh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := fakeServer.New()
+			defer s.Close()
+			if tt.response != "" {
+				s.Handle(http.MethodPost, "/oauth2/get_token", fakeServer.Response{ContentType: "application/json", Body: []byte(tt.response)})
+			}
+			// Within the expiry margin, so the token is refreshed before use
+			yc, store := storedClient(t, s, yahoo.YahooAuth{
+				AccessToken:  "stored-access",
+				RefreshToken: "stored-refresh",
+				Expiry:       authNow.Add(time.Minute),
+			})
+
+			if err := yc.Authenticate(); err != nil {
+				t.Fatal(err)
+			}
+			tokens := s.RequestsTo(http.MethodPost, "/oauth2/get_token")
+			if len(tokens) != 1 {
+				t.Fatalf("got %d token requests, want 1", len(tokens))
+			}
+			form, err := url.ParseQuery(string(tokens[0].Body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if form.Get("refresh_token") != "stored-refresh" {
+				t.Errorf("refreshed with %q, want the stored refresh token", form.Get("refresh_token"))
+			}
+
+			saved, err := store.Load()
+			if err != nil || saved == nil {
+				t.Fatalf("Load() = %v, %v", saved, err)
+			}
+			if saved.RefreshToken != tt.wantRefresh {
+				t.Errorf("saved refresh token = %q, want %q", saved.RefreshToken, tt.wantRefresh)
+			}
+			if !saved.Expiry.Equal(authNow.Add(time.Hour)) {
+				t.Errorf("saved expiry = %s, want an hour from now", saved.Expiry)
+			}
+		})
+	}
+}
+
+func TestConcurrentCallersShareOneRefresh(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	yc := s.YahooClient()
+	yc.Clock = clock.Fixed(authNow)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = yc.Authenticate()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(s.RequestsTo(http.MethodPost, "/oauth2/get_token")); n != 1 {
+		t.Errorf("got %d token requests from 10 callers, want 1", n)
+	}
+}
@@ -0,0 +1,86 @@
+package yahoo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"hockey-hacks/pkg/email"
+	"log"
+	"net/http"
+)
+
+// AddBudget is a team's use of the league's weekly roster adds.
+type AddBudget struct {
+	Week int
+	// Max is 0 in leagues without a weekly add limit
+	Max  int
+	Used int
+	// Moves is the number of add/drops made this season
+	Moves int
+}
+
+// NewAddBudget returns the team's add budget for the current week.
+func NewAddBudget(league League, team Team) AddBudget {
+	return AddBudget{
+		Week:  team.RosterAdds.CoverageValue,
+		Max:   league.Settings.MaxWeeklyAdds,
+		Used:  team.RosterAdds.Value,
+		Moves: team.NumberOfMoves,
+	}
+}
+
+// Limited reports whether the league limits weekly adds.
+func (b AddBudget) Limited() bool {
+	return b.Max > 0
+}
+
+// Remaining returns the adds left this week. It is only meaningful when
+// the budget is Limited.
+func (b AddBudget) Remaining() int {
+	if b.Used >= b.Max {
+		return 0
+	}
+	return b.Max - b.Used
+}
+
+// CanAdd reports whether another player may be added this week.
+func (b AddBudget) CanAdd() bool {
+	return !b.Limited() || b.Remaining() > 0
+}
+
+func (b AddBudget) String() string {
+	if !b.Limited() {
+		return fmt.Sprintf("%d adds used in week %d, no weekly limit", b.Used, b.Week)
+	}
+	return fmt.Sprintf("%d of %d adds used in week %d", b.Used, b.Max, b.Week)
+}
+
+// GetTeam returns the team's metadata, including its roster adds.
+func (yc *YahooClient) GetTeam(teamKey string) (Team, error) {
+	url := yc.BaseURL + "/team/" + teamKey
+	respBody, err := yc.sendXMLRequest(http.MethodGet, url, nil)
+	if err != nil {
+		email.Notify(yc.EnableEmail, respBody)
+		log.Println("Failed to get team:", err)
+		return Team{}, err
+	}
+
+	var fantasyContent FantasyContent
+	if err := xml.Unmarshal(respBody, &fantasyContent); err != nil {
+		log.Println("Error unmarshaling XML:", err)
+		return Team{}, err
+	}
+	return fantasyContent.Team, nil
+}
+
+// GetAddBudget returns the team's add budget for the current week.
+func (yc *YahooClient) GetAddBudget(teamKey string) (AddBudget, error) {
+	league, err := yc.GetLeague(LeagueKey(teamKey))
+	if err != nil {
+		return AddBudget{}, err
+	}
+	team, err := yc.GetTeam(teamKey)
+	if err != nil {
+		return AddBudget{}, err
+	}
+	return NewAddBudget(league, team), nil
+}
@@ -0,0 +1,143 @@
+package yahoo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrUndroppable is returned when asked to drop an undroppable player or a
+// keeper.
+var ErrUndroppable = errors.New("player cannot be dropped")
+
+// ErrAddLimit is returned when adding a player would exceed the league's
+// weekly add limit.
+var ErrAddLimit = errors.New("weekly add limit reached")
+
+// ErrorKind classifies a failed Yahoo API request.
+type ErrorKind int
+
+const (
+	ErrorUnknown ErrorKind = iota
+	ErrorAuthExpired
+	ErrorForbidden
+	ErrorNotFound
+	ErrorRateLimited
+	ErrorRosterLocked
+	ErrorInvalidPosition
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorAuthExpired:
+		return "auth expired"
+	case ErrorForbidden:
+		return "forbidden"
+	case ErrorNotFound:
+		return "not found"
+	case ErrorRateLimited:
+		return "rate limited"
+	case ErrorRosterLocked:
+		return "roster locked"
+	case ErrorInvalidPosition:
+		return "invalid position"
+	}
+	return "unknown"
+}
+
+// ErrorResponse is the <error> document Yahoo returns on failure.
+type ErrorResponse struct {
+	XMLName     xml.Name `xml:"error"`
+	Description string   `xml:"description"`
+	Detail      string   `xml:"detail"`
+}
+
+// APIError is returned for any non-2xx Yahoo API response. Use errors.As
+// to inspect the Kind.
+type APIError struct {
+	StatusCode  int
+	Kind        ErrorKind
+	Description string
+	Body        []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("yahoo: %s (status %d)", e.Kind, e.StatusCode)
+	}
+	return fmt.Sprintf("yahoo: %s (status %d): %s", e.Kind, e.StatusCode, e.Description)
+}
+
+// newAPIError parses a failed response body into an APIError.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	var errResp ErrorResponse
+	xml.Unmarshal(body, &errResp)
+
+	description := strings.TrimSpace(errResp.Description)
+	if description == "" {
+		description = strings.TrimSpace(string(body))
+	}
+
+	return &APIError{
+		StatusCode:  resp.StatusCode,
+		Kind:        errorKind(resp, description),
+		Description: description,
+		Body:        body,
+	}
+}
+
+// TokenErrorResponse is the JSON document the OAuth token endpoint returns
+// on failure.
+type TokenErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// newTokenError parses a failed token endpoint response. Only a rejected
+// refresh token (invalid_grant) means the authorization has expired; other
+// failures, such as bad client credentials, keep their own kind.
+func newTokenError(resp *http.Response, body []byte) *APIError {
+	e := newAPIError(resp, body)
+	var tokenErr TokenErrorResponse
+	if json.Unmarshal(body, &tokenErr) == nil && tokenErr.Error != "" {
+		e.Description = tokenErr.Error
+		if tokenErr.ErrorDescription != "" {
+			e.Description += ": " + tokenErr.ErrorDescription
+		}
+	}
+	rejected := resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized
+	switch {
+	case rejected && tokenErr.Error == "invalid_grant":
+		e.Kind = ErrorAuthExpired
+	case e.Kind == ErrorAuthExpired:
+		e.Kind = ErrorUnknown
+	}
+	return e
+}
+
+func errorKind(resp *http.Response, description string) ErrorKind {
+	d := strings.ToLower(description)
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized,
+		strings.Contains(d, "token_expired"),
+		strings.Contains(resp.Header.Get("WWW-Authenticate"), "token_expired"):
+		return ErrorAuthExpired
+	// Yahoo answers throttled requests with a non-standard 999
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == 999,
+		strings.Contains(d, "rate limit"):
+		return ErrorRateLimited
+	case strings.Contains(d, "lock"), strings.Contains(d, "already started"),
+		strings.Contains(d, "not editable"), strings.Contains(d, "cannot be changed"):
+		return ErrorRosterLocked
+	case strings.Contains(d, "position"):
+		return ErrorInvalidPosition
+	case resp.StatusCode == http.StatusForbidden:
+		return ErrorForbidden
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrorNotFound
+	}
+	return ErrorUnknown
+}
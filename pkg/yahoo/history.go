@@ -0,0 +1,87 @@
+package yahoo
+
+import (
+	"encoding/xml"
+	"hockey-hacks/pkg/audit"
+	"hockey-hacks/pkg/email"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GetTransactions returns the league's recent transactions of the given
+// types (add, drop, trade, commish), newest first. Without types every
+// transaction is returned.
+func (yc *YahooClient) GetTransactions(leagueKey string, types ...string) ([]Transaction, error) {
+	url := yc.BaseURL + "/league/" + leagueKey + "/transactions"
+	if len(types) > 0 {
+		url += ";types=" + strings.Join(types, ",")
+	}
+	respBody, err := yc.sendXMLRequest(http.MethodGet, url, nil)
+	if err != nil {
+		email.Notify(yc.EnableEmail, respBody)
+		log.Println("Failed to get transactions:", err)
+		return nil, err
+	}
+
+	var fantasyContent FantasyContent
+	if err := xml.Unmarshal(respBody, &fantasyContent); err != nil {
+		log.Println("Error unmarshaling XML:", err)
+		return nil, err
+	}
+	return fantasyContent.League.Transactions.Transaction, nil
+}
+
+// AuditEntry returns the transaction as an audit log entry.
+func (t Transaction) AuditEntry() audit.Entry {
+	e := audit.Entry{
+		Time:           time.Unix(t.Timestamp, 0),
+		Source:         audit.SourceLeague,
+		Type:           t.Type,
+		TransactionKey: t.TransactionKey,
+		Status:         t.Status,
+		TeamKey:        t.TraderTeamKey,
+	}
+	for _, p := range t.Players.Player {
+		d := p.TransactionData
+		from, to := d.SourceType, d.DestinationType
+		if d.SourceTeamName != "" {
+			from = d.SourceTeamName
+		} else if d.SourceTeamKey != "" {
+			from = d.SourceTeamKey
+		}
+		if d.DestinationTeamName != "" {
+			to = d.DestinationTeamName
+		} else if d.DestinationTeamKey != "" {
+			to = d.DestinationTeamKey
+		}
+		if e.TeamKey == "" {
+			e.TeamKey = d.DestinationTeamKey
+			if e.TeamKey == "" {
+				e.TeamKey = d.SourceTeamKey
+			}
+		}
+		e.Players = append(e.Players, audit.Player{
+			Key:    p.PlayerKey,
+			Name:   p.Name.Full,
+			Detail: d.Type + ": " + from + " -> " + to,
+		})
+	}
+	return e
+}
+
+// audit records a mutating request in the audit log, when there is one.
+func (yc *YahooClient) audit(e audit.Entry, err error) {
+	if yc.Audit == nil {
+		return
+	}
+	e.Time = yc.Clock.Now()
+	e.Source = audit.SourceClient
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if err := yc.Audit.Append(e); err != nil {
+		log.Println("Failed to write audit log:", err)
+	}
+}
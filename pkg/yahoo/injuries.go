@@ -0,0 +1,173 @@
+package yahoo
+
+import "fmt"
+
+// irPositions are the injured reserve and not-active slots, in the order
+// players are placed in them so that IR+ stays open for players only it
+// accepts.
+var irPositions = []string{PositionIR, PositionNA, PositionIRPlus}
+
+// statusPositions lists the injury statuses each slot accepts, for players
+// whose eligible positions do not list the slot yet.
+var statusPositions = map[string][]string{
+	PositionIR:     {"IR", "IR-LT", "IR-NR"},
+	PositionIRPlus: {"IR", "IR-LT", "IR-NR", "O"},
+	PositionNA:     {"NA"},
+}
+
+func isIRPosition(position string) bool {
+	for _, pos := range irPositions {
+		if pos == position {
+			return true
+		}
+	}
+	return false
+}
+
+// CanFill reports whether the player may be placed in the IR, IR+ or NA
+// slot, going by the player's eligible positions and injury status.
+func (p Player) CanFill(position string) bool {
+	if p.IsEligible(position) {
+		return true
+	}
+	for _, status := range statusPositions[position] {
+		if p.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// PositionCount returns how many of the position the league's roster has,
+// active or not.
+func (s LeagueSettings) PositionCount(position string) int {
+	count := 0
+	for _, rp := range s.RosterPositions.RosterPosition {
+		if rp.Position == position {
+			count += rp.Count
+		}
+	}
+	return count
+}
+
+// PlanInjuries moves injured and not-active players into open IR, IR+ or NA
+// slots, freeing their roster spot, and moves players back out once they
+// are no longer eligible for their slot. A player can only come back when
+// a bench or active spot is open, since anything else leaves the roster
+// illegal; those moves are refused and reported instead, as are moves of
+// locked players.
+func PlanInjuries(roster Players, settings LeagueSettings, locked map[string]bool) ([]Move, []string) {
+	if len(settings.RosterPositions.RosterPosition) == 0 {
+		return nil, []string{"league roster positions unknown, injured reserve left alone"}
+	}
+
+	used := make(map[string]int)
+	for _, p := range roster.PlayerList {
+		used[p.SelectedPosition.Position]++
+	}
+	open := func(position string) bool {
+		return used[position] < settings.PositionCount(position)
+	}
+	move := func(p Player, position string) Move {
+		used[p.SelectedPosition.Position]--
+		used[position]++
+		return NewMove(p, position, "")
+	}
+
+	var moves []Move
+	var report []string
+	for _, p := range roster.PlayerList {
+		if isIRPosition(p.SelectedPosition.Position) {
+			continue
+		}
+		injured := false
+		for _, pos := range irPositions {
+			if !p.CanFill(pos) {
+				continue
+			}
+			injured = true
+			if open(pos) && !locked[p.PlayerKey] {
+				m := move(p, pos)
+				m.Reason = injuryReason(p)
+				moves = append(moves, m)
+				injured = false
+				break
+			}
+		}
+		if injured && locked[p.PlayerKey] {
+			report = append(report, fmt.Sprintf("%s is %s but the lineup spot is locked", p.Name.Full, injuryReason(p)))
+			continue
+		}
+		if injured {
+			report = append(report, fmt.Sprintf("%s is %s but no IR slot is open", p.Name.Full, injuryReason(p)))
+		}
+	}
+
+	for _, p := range roster.PlayerList {
+		if !isIRPosition(p.SelectedPosition.Position) || p.CanFill(p.SelectedPosition.Position) {
+			continue
+		}
+		if locked[p.PlayerKey] {
+			report = append(report, fmt.Sprintf("%s can no longer stay in %s but the lineup spot is locked", p.Name.Full, p.SelectedPosition.Position))
+			continue
+		}
+		if m, ok := activate(p, settings, open, move); ok {
+			moves = append(moves, m)
+			continue
+		}
+		report = append(report, fmt.Sprintf("%s can no longer stay in %s but no roster spot is open; drop a player to activate them", p.Name.Full, p.SelectedPosition.Position))
+	}
+	return moves, report
+}
+
+// activate moves a player who no longer belongs in an IR slot to another
+// IR slot the player fits, the bench, or an open active slot.
+func activate(p Player, settings LeagueSettings, open func(string) bool, move func(Player, string) Move) (Move, bool) {
+	for _, pos := range irPositions {
+		if p.CanFill(pos) && open(pos) {
+			m := move(p, pos)
+			m.Reason = injuryReason(p)
+			return m, true
+		}
+	}
+	if open(PositionBench) {
+		m := move(p, PositionBench)
+		m.Reason = "activated from " + p.SelectedPosition.Position
+		return m, true
+	}
+	for _, rp := range settings.RosterPositions.RosterPosition {
+		if rp.IsStartingPosition == 1 && p.IsEligible(rp.Position) && open(rp.Position) {
+			m := move(p, rp.Position)
+			m.Reason = "activated from " + p.SelectedPosition.Position
+			return m, true
+		}
+	}
+	return Move{}, false
+}
+
+func injuryReason(p Player) string {
+	if p.StatusFull != "" {
+		return p.StatusFull
+	}
+	if p.Status != "" {
+		return "status " + p.Status
+	}
+	return "eligible for injured reserve"
+}
+
+// WithMoves returns a copy of the roster with the moves applied, so later
+// planning sees the slots they free.
+func (ps Players) WithMoves(moves []Move) Players {
+	position := make(map[string]string)
+	for _, m := range moves {
+		position[m.PlayerKey] = m.Position
+	}
+	moved := Players{PlayerList: make([]Player, len(ps.PlayerList))}
+	for i, p := range ps.PlayerList {
+		if pos, ok := position[p.PlayerKey]; ok {
+			p.SelectedPosition.Position = pos
+		}
+		moved.PlayerList[i] = p
+	}
+	return moved
+}
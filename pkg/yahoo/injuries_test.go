@@ -0,0 +1,149 @@
+package yahoo_test
+
+import (
+	"hockey-hacks/pkg/yahoo"
+	"strings"
+	"testing"
+)
+
+// injurySettings is a league with one C, one bench, IR, IR+ and NA slot.
+func injurySettings() yahoo.LeagueSettings {
+	var s yahoo.LeagueSettings
+	s.RosterPositions.RosterPosition = []yahoo.RosterPosition{
+		{Position: "C", PositionType: yahoo.PositionTypeSkater, Count: 1, IsStartingPosition: 1},
+		{Position: yahoo.PositionBench, Count: 1},
+		{Position: yahoo.PositionIR, Count: 1},
+		{Position: yahoo.PositionIRPlus, Count: 1},
+		{Position: yahoo.PositionNA, Count: 1},
+	}
+	return s
+}
+
+// center returns a C in the slot with the injury status.
+func center(name, slot, status string) yahoo.Player {
+	p := yahoo.Player{PlayerKey: name, EditorialTeamAbbr: "Tor", Status: status}
+	p.Name.Full = name
+	p.EligiblePositions.Positions = []string{"C"}
+	p.SelectedPosition.Position = slot
+	return p
+}
+
+func TestPlanInjuries(t *testing.T) {
+	tests := []struct {
+		name   string
+		roster []yahoo.Player
+		locked []string
+		want   map[string]string
+		report string
+	}{
+		{
+			name:   "injured player to IR",
+			roster: []yahoo.Player{center("Hurt", "C", "IR")},
+			want:   map[string]string{"Hurt": yahoo.PositionIR},
+		},
+		{
+			name:   "day-to-day out only fits IR+",
+			roster: []yahoo.Player{center("Out", yahoo.PositionBench, "O")},
+			want:   map[string]string{"Out": yahoo.PositionIRPlus},
+		},
+		{
+			name:   "not active player to NA",
+			roster: []yahoo.Player{center("Prospect", yahoo.PositionBench, "NA")},
+			want:   map[string]string{"Prospect": yahoo.PositionNA},
+		},
+		{
+			name: "IR slots full",
+			roster: []yahoo.Player{
+				center("InIR", yahoo.PositionIR, "IR"),
+				center("InIRPlus", yahoo.PositionIRPlus, "IR-LT"),
+				center("Hurt", "C", "IR"),
+			},
+			want:   map[string]string{},
+			report: "Hurt is status IR but no IR slot is open",
+		},
+		{
+			name:   "healthy player back to the open bench",
+			roster: []yahoo.Player{center("Healed", yahoo.PositionIR, "")},
+			want:   map[string]string{"Healed": yahoo.PositionBench},
+		},
+		{
+			name: "healthy player back to an open active slot",
+			roster: []yahoo.Player{
+				center("Bench", yahoo.PositionBench, ""),
+				center("Healed", yahoo.PositionIR, ""),
+			},
+			want: map[string]string{"Healed": "C"},
+		},
+		{
+			name: "healthy player with no roster spot open",
+			roster: []yahoo.Player{
+				center("Starter", "C", ""),
+				center("Bench", yahoo.PositionBench, ""),
+				center("Healed", yahoo.PositionIR, ""),
+			},
+			want:   map[string]string{},
+			report: "Healed can no longer stay in IR but no roster spot is open",
+		},
+		{
+			name:   "healthy player swaps out for an injured one",
+			roster: []yahoo.Player{center("Hurt", yahoo.PositionBench, "IR"), center("Healed", yahoo.PositionIR, "")},
+			// Hurt takes IR+ since IR is still held, which frees the bench
+			want: map[string]string{"Hurt": yahoo.PositionIRPlus, "Healed": yahoo.PositionBench},
+		},
+		{
+			name:   "locked injured player stays",
+			roster: []yahoo.Player{center("Hurt", "C", "IR")},
+			locked: []string{"Hurt"},
+			want:   map[string]string{},
+			report: "Hurt is status IR but the lineup spot is locked",
+		},
+		{
+			name:   "locked healthy player stays in IR",
+			roster: []yahoo.Player{center("Healed", yahoo.PositionIR, "")},
+			locked: []string{"Healed"},
+			want:   map[string]string{},
+			report: "Healed can no longer stay in IR but the lineup spot is locked",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locked := make(map[string]bool)
+			for _, key := range tt.locked {
+				locked[key] = true
+			}
+			roster := yahoo.Players{PlayerList: tt.roster}
+			moves, report := yahoo.PlanInjuries(roster, injurySettings(), locked)
+
+			got := positions(moves)
+			if len(got) != len(tt.want) {
+				t.Errorf("moves = %v, want %v", got, tt.want)
+			}
+			for name, pos := range tt.want {
+				if got[name] != pos {
+					t.Errorf("%s moved to %q, want %q", name, got[name], pos)
+				}
+			}
+			if tt.report == "" && len(report) > 0 {
+				t.Errorf("unexpected report %q", report)
+			}
+			if tt.report != "" && (len(report) != 1 || !strings.Contains(report[0], tt.report)) {
+				t.Errorf("report = %q, want %q", report, tt.report)
+			}
+
+			// The moves applied to the roster leave every player where the
+			// plan put them.
+			for _, p := range roster.WithMoves(moves).PlayerList {
+				if pos, ok := got[p.PlayerKey]; ok && p.SelectedPosition.Position != pos {
+					t.Errorf("WithMoves left %s in %s, want %s", p.PlayerKey, p.SelectedPosition.Position, pos)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanInjuriesUnknownRosterPositions(t *testing.T) {
+	moves, report := yahoo.PlanInjuries(yahoo.Players{PlayerList: []yahoo.Player{center("Hurt", "C", "IR")}}, yahoo.LeagueSettings{}, nil)
+	if len(moves) != 0 || len(report) != 1 {
+		t.Errorf("got moves %v and report %q, want no moves and one warning", moves, report)
+	}
+}
@@ -0,0 +1,141 @@
+package yahoo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"hockey-hacks/pkg/email"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Lineup coverage types
+	CoverageDate = "date"
+	CoverageWeek = "week"
+)
+
+// Coverage is the period a roster read or lineup change applies to: a
+// single date for daily leagues or a fantasy week for weekly ones.
+type Coverage struct {
+	Type string
+	Date time.Time
+	Week int
+}
+
+// DateCoverage covers a single day.
+func DateCoverage(date time.Time) Coverage {
+	return Coverage{Type: CoverageDate, Date: date}
+}
+
+// WeekCoverage covers a whole fantasy week.
+func WeekCoverage(week int) Coverage {
+	return Coverage{Type: CoverageWeek, Week: week}
+}
+
+func (c Coverage) String() string {
+	if c.Type == CoverageWeek {
+		return "week " + strconv.Itoa(c.Week)
+	}
+	return c.Date.Format(time.DateOnly)
+}
+
+// param returns the matrix parameter selecting the coverage in a URL.
+func (c Coverage) param() string {
+	if c.Type == CoverageWeek {
+		return ";week=" + strconv.Itoa(c.Week)
+	}
+	return ";date=" + c.Date.Format(time.DateOnly)
+}
+
+// LineupCoverage returns CoverageWeek for leagues whose lineups lock once a
+// week and CoverageDate for daily leagues.
+func (l League) LineupCoverage() string {
+	if l.Settings.LockType() == LockWeekly {
+		return CoverageWeek
+	}
+	return CoverageDate
+}
+
+// GameKey returns the game part of a league or team key, e.g. 465 for
+// 465.l.1234.
+func GameKey(key string) string {
+	return strings.SplitN(key, ".", 2)[0]
+}
+
+// GetLeague returns the league's metadata and settings.
+func (yc *YahooClient) GetLeague(leagueKey string) (League, error) {
+	url := yc.BaseURL + "/league/" + leagueKey + "/settings"
+	respBody, err := yc.sendXMLRequest(http.MethodGet, url, nil)
+	if err != nil {
+		email.Notify(yc.EnableEmail, respBody)
+		log.Println("Failed to get league settings:", err)
+		return League{}, err
+	}
+
+	var fantasyContent FantasyContent
+	if err := xml.Unmarshal(respBody, &fantasyContent); err != nil {
+		log.Println("Error unmarshaling XML:", err)
+		return League{}, err
+	}
+	return fantasyContent.League, nil
+}
+
+// GetGameWeeks returns the start and end date of every fantasy week.
+func (yc *YahooClient) GetGameWeeks(gameKey string) ([]GameWeek, error) {
+	url := yc.BaseURL + "/game/" + gameKey + "/game_weeks"
+	respBody, err := yc.sendXMLRequest(http.MethodGet, url, nil)
+	if err != nil {
+		email.Notify(yc.EnableEmail, respBody)
+		log.Println("Failed to get game weeks:", err)
+		return nil, err
+	}
+
+	var fantasyContent FantasyContent
+	if err := xml.Unmarshal(respBody, &fantasyContent); err != nil {
+		log.Println("Error unmarshaling XML:", err)
+		return nil, err
+	}
+	return fantasyContent.Game.GameWeeks.GameWeek, nil
+}
+
+// WeekOf returns the fantasy week containing the date.
+func WeekOf(weeks []GameWeek, date time.Time) (GameWeek, error) {
+	day := date.Format(time.DateOnly)
+	for _, w := range weeks {
+		if w.Start <= day && day <= w.End {
+			return w, nil
+		}
+	}
+	return GameWeek{}, fmt.Errorf("no fantasy week contains %s", day)
+}
+
+// NextWeek returns the fantasy week after the given one, if the season has
+// one.
+func NextWeek(weeks []GameWeek, week GameWeek) (GameWeek, bool) {
+	for _, w := range weeks {
+		if w.Week == week.Week+1 {
+			return w, true
+		}
+	}
+	return GameWeek{}, false
+}
+
+// Dates returns every day of the week.
+func (w GameWeek) Dates(loc *time.Location) ([]time.Time, error) {
+	start, err := time.ParseInLocation(time.DateOnly, w.Start, loc)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.ParseInLocation(time.DateOnly, w.End, loc)
+	if err != nil {
+		return nil, err
+	}
+	var dates []time.Time
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	return dates, nil
+}
@@ -0,0 +1,51 @@
+package yahoo
+
+import (
+	"hockey-hacks/pkg/sportsData"
+	"time"
+)
+
+// LockTime answers whether the team plays on the date and when its players'
+// lineup spots lock: at the team's game in leagues that lock at game time,
+// otherwise at the day's first game. The lock time is zero when the schedule
+// has no start time.
+func (s LeagueSettings) LockTime(sd *sportsData.Client, team string, date time.Time) (time.Time, bool, error) {
+	games, err := sd.GetGames(date)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	g, ok := games.TeamGame(team)
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	if s.LockType() == LockGameTime {
+		start, _ := g.Start()
+		return start, true, nil
+	}
+	first, _ := games.FirstStart()
+	return first, true, nil
+}
+
+// Locked returns the keys of the rostered players whose lineup spots can no
+// longer change at the given time, because their game or, in daily-lock
+// leagues, the day's first game has started. Planning keeps them where they
+// are so the rest of the lineup is built around them.
+func Locked(roster Players, games sportsData.Games, settings LeagueSettings, now time.Time) map[string]bool {
+	dailyLock := false
+	if settings.LockType() != LockGameTime {
+		first, ok := games.FirstStart()
+		dailyLock = ok && !now.Before(first)
+	}
+	locked := make(map[string]bool)
+	for _, p := range roster.PlayerList {
+		if g, ok := games.TeamGame(p.Team()); dailyLock || ok && g.Started(now) {
+			locked[p.PlayerKey] = true
+		}
+	}
+	return locked
+}
+
+// LockedMove keeps a locked player in the current slot.
+func LockedMove(p Player) Move {
+	return NewMove(p, p.SelectedPosition.Position, "lineup spot locked")
+}
@@ -0,0 +1,42 @@
+package yahoo_test
+
+import (
+	"hockey-hacks/pkg/fakeServer"
+	"hockey-hacks/pkg/yahoo"
+	"testing"
+	"time"
+)
+
+func TestLockTime(t *testing.T) {
+	s := fakeServer.New()
+	defer s.Close()
+	sd := s.SportsDataClient()
+	date := time.Date(2024, 10, 19, 0, 0, 0, 0, time.UTC)
+	first := time.Date(2024, 10, 19, 23, 0, 0, 0, time.UTC)
+	late := time.Date(2024, 10, 20, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		deadline string
+		team     string
+		plays    bool
+		lock     time.Time
+	}{
+		{name: "daily lock at the first game", team: "UTA", plays: true, lock: first},
+		{name: "game time lock at the team's game", deadline: "intraday", team: "UTA", plays: true, lock: late},
+		{name: "team not playing", team: "EDM"},
+		{name: "postponed game", deadline: "intraday", team: "TB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := yahoo.LeagueSettings{WeeklyDeadline: tt.deadline}
+			lock, plays, err := settings.LockTime(sd, tt.team, date)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plays != tt.plays || !lock.Equal(tt.lock) {
+				t.Errorf("LockTime(%s) = %v, %v, want %v, %v", tt.team, lock, plays, tt.lock, tt.plays)
+			}
+		})
+	}
+}
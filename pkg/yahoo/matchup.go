@@ -0,0 +1,163 @@
+package yahoo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"hockey-hacks/pkg/email"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// Matchup statuses
+	MatchupPreEvent   = "preevent"
+	MatchupInProgress = "midevent"
+	MatchupFinished   = "postevent"
+)
+
+// CategoryScore is one scoring category of a matchup.
+type CategoryScore struct {
+	Category StatCategory
+	Ours     string
+	Theirs   string
+	// Leader is the key of the team ahead in the category, empty when tied
+	Leader string
+}
+
+// Stat returns the team's value for the stat in the matchup, if it has one.
+func (t Team) Stat(statID int) (float64, bool) {
+	return statValue(t.TeamStats.Stats.Stat, statID)
+}
+
+func (t Team) statString(statID int) string {
+	for _, s := range t.TeamStats.Stats.Stat {
+		if s.StatID == statID {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// Sides returns the given team and its opponent in the matchup.
+func (m Matchup) Sides(teamKey string) (us Team, them Team, err error) {
+	found := false
+	for _, t := range m.Teams.Team {
+		if t.TeamKey == teamKey {
+			us, found = t, true
+		} else {
+			them = t
+		}
+	}
+	if !found {
+		return us, them, fmt.Errorf("%s is not in the week %d matchup", teamKey, m.Week)
+	}
+	return us, them, nil
+}
+
+// Categories returns the matchup's scoring categories from the team's side,
+// in the league's order. The leader is taken from Yahoo's stat winners,
+// or worked out from the values when Yahoo has not decided them yet.
+func (m Matchup) Categories(teamKey string, settings LeagueSettings) ([]CategoryScore, error) {
+	us, them, err := m.Sides(teamKey)
+	if err != nil {
+		return nil, err
+	}
+	winners := make(map[int]StatWinner)
+	for _, w := range m.StatWinners.StatWinner {
+		winners[w.StatID] = w
+	}
+
+	var scores []CategoryScore
+	for _, c := range settings.StatCategories.Stats.Stat {
+		if _, ok := settings.Category(c.StatID); !ok {
+			continue
+		}
+		score := CategoryScore{Category: c, Ours: us.statString(c.StatID), Theirs: them.statString(c.StatID)}
+		if w, ok := winners[c.StatID]; ok {
+			if w.IsTied == 0 {
+				score.Leader = w.WinnerTeamKey
+			}
+		} else {
+			score.Leader = leader(c, us, them)
+		}
+		scores = append(scores, score)
+	}
+	return scores, nil
+}
+
+// leader returns the key of the team ahead in the category.
+func leader(c StatCategory, us Team, them Team) string {
+	ours, okOurs := us.Stat(c.StatID)
+	theirs, okTheirs := them.Stat(c.StatID)
+	switch {
+	case !okOurs || !okTheirs || ours == theirs:
+		return ""
+	case (ours > theirs) == (c.SortOrder == 1):
+		return us.TeamKey
+	}
+	return them.TeamKey
+}
+
+// GetScoreboard returns every matchup of the league for the week, or the
+// current week when week is 0.
+func (yc *YahooClient) GetScoreboard(leagueKey string, week int) (Scoreboard, error) {
+	url := yc.BaseURL + "/league/" + leagueKey + "/scoreboard"
+	if week != 0 {
+		url += ";week=" + strconv.Itoa(week)
+	}
+	respBody, err := yc.sendXMLRequest(http.MethodGet, url, nil)
+	if err != nil {
+		email.Notify(yc.EnableEmail, respBody)
+		log.Println("Failed to get scoreboard:", err)
+		return Scoreboard{}, err
+	}
+
+	var fantasyContent FantasyContent
+	if err := xml.Unmarshal(respBody, &fantasyContent); err != nil {
+		log.Println("Error unmarshaling XML:", err)
+		return Scoreboard{}, err
+	}
+	return fantasyContent.League.Scoreboard, nil
+}
+
+// GetMatchup returns the team's matchup for the week, or the current week
+// when week is 0.
+func (yc *YahooClient) GetMatchup(teamKey string, week int) (Matchup, error) {
+	url := yc.BaseURL + "/team/" + teamKey + "/matchups"
+	if week != 0 {
+		url += ";weeks=" + strconv.Itoa(week)
+	}
+	respBody, err := yc.sendXMLRequest(http.MethodGet, url, nil)
+	if err != nil {
+		email.Notify(yc.EnableEmail, respBody)
+		log.Println("Failed to get matchup:", err)
+		return Matchup{}, err
+	}
+
+	var fantasyContent FantasyContent
+	if err := xml.Unmarshal(respBody, &fantasyContent); err != nil {
+		log.Println("Error unmarshaling XML:", err)
+		return Matchup{}, err
+	}
+
+	matchups := fantasyContent.Team.Matchups.Matchup
+	if week == 0 {
+		// Without a week Yahoo returns the whole season so far
+		for _, m := range matchups {
+			if m.Status == MatchupInProgress {
+				return m, nil
+			}
+		}
+		if len(matchups) > 0 {
+			return matchups[len(matchups)-1], nil
+		}
+	} else {
+		for _, m := range matchups {
+			if m.Week == week {
+				return m, nil
+			}
+		}
+	}
+	return Matchup{}, fmt.Errorf("no matchup for %s in week %d", teamKey, week)
+}
@@ -1,13 +1,17 @@
 // File: yahoo/models.go
 package yahoo
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"time"
+)
 
 type YahooAuth struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	ExpiresIn    int    `json:"expires_in"`
-	TokenType    string `json:"token_type"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresIn    int       `json:"expires_in"`
+	TokenType    string    `json:"token_type"`
+	Expiry       time.Time `json:"expiry"`
 }
 
 type SwapPlayer struct {
@@ -19,7 +23,8 @@ type SwapPlayerRequest struct {
 	XMLName xml.Name `xml:"fantasy_content"`
 	Roster  struct {
 		CoverageType string `xml:"coverage_type"`
-		Date         string `xml:"date"`
+		Date         string `xml:"date,omitempty"`
+		Week         int    `xml:"week,omitempty"`
 		Players      struct {
 			Player []SwapPlayer `xml:"player"`
 		} `xml:"players"`
@@ -35,16 +40,31 @@ type AddDropPlayer struct {
 	} `xml:"transaction_data"`
 }
 
+// AddDropPlayerRequest is the body of an add/drop, which lists both
+// players under <players>. Adds, drops and waiver claims of a single player
+// use SinglePlayerRequest instead.
 type AddDropPlayerRequest struct {
 	XMLName     xml.Name `xml:"fantasy_content"`
 	Transaction struct {
 		Type    string `xml:"type"`
+		FAABBid *int   `xml:"faab_bid,omitempty"`
 		Players struct {
 			AddDropPlayer []AddDropPlayer `xml:"player"`
 		} `xml:"players"`
 	} `xml:"transaction"`
 }
 
+// SinglePlayerRequest is the body of an add, drop or waiver claim, whose
+// one player sits directly under <transaction>.
+type SinglePlayerRequest struct {
+	XMLName     xml.Name `xml:"fantasy_content"`
+	Transaction struct {
+		Type    string        `xml:"type"`
+		FAABBid *int          `xml:"faab_bid,omitempty"`
+		Player  AddDropPlayer `xml:"player"`
+	} `xml:"transaction"`
+}
+
 type FantasyContent struct {
 	XMLName        xml.Name `xml:"fantasy_content"`
 	XMLLang        string   `xml:"xml:lang,attr"`
@@ -55,7 +75,138 @@ type FantasyContent struct {
 	YahooNamespace string   `xml:"xmlns:yahoo,attr"`
 	Namespace      string   `xml:"xmlns,attr"`
 
-	Team Team `xml:"team"`
+	Team        Team        `xml:"team"`
+	League      League      `xml:"league"`
+	Game        Game        `xml:"game"`
+	Transaction Transaction `xml:"transaction"`
+}
+
+type Transaction struct {
+	TransactionKey string `xml:"transaction_key"`
+	TransactionID  int    `xml:"transaction_id"`
+	Type           string `xml:"type"`
+	Status         string `xml:"status"`
+	Timestamp      int64  `xml:"timestamp"`
+	FAABBid        int    `xml:"faab_bid"`
+	TraderTeamKey  string `xml:"trader_team_key"`
+	TradeeTeamKey  string `xml:"tradee_team_key"`
+	Players        struct {
+		Player []TransactionPlayer `xml:"player"`
+	} `xml:"players"`
+}
+
+type TransactionPlayer struct {
+	PlayerKey         string          `xml:"player_key"`
+	Name              Name            `xml:"name"`
+	EditorialTeamAbbr string          `xml:"editorial_team_abbr"`
+	TransactionData   TransactionData `xml:"transaction_data"`
+}
+
+type TransactionData struct {
+	Type                string `xml:"type"`
+	SourceType          string `xml:"source_type"`
+	SourceTeamKey       string `xml:"source_team_key"`
+	SourceTeamName      string `xml:"source_team_name"`
+	DestinationType     string `xml:"destination_type"`
+	DestinationTeamKey  string `xml:"destination_team_key"`
+	DestinationTeamName string `xml:"destination_team_name"`
+}
+
+type League struct {
+	LeagueKey    string         `xml:"league_key"`
+	LeagueID     int            `xml:"league_id"`
+	Name         string         `xml:"name"`
+	URL          string         `xml:"url"`
+	NumTeams     int            `xml:"num_teams"`
+	ScoringType  string         `xml:"scoring_type"`
+	CurrentWeek  int            `xml:"current_week"`
+	StartWeek    int            `xml:"start_week"`
+	StartDate    string         `xml:"start_date"`
+	EndWeek      int            `xml:"end_week"`
+	EndDate      string         `xml:"end_date"`
+	Season       int            `xml:"season"`
+	Settings     LeagueSettings `xml:"settings"`
+	Players      Players        `xml:"players"`
+	Scoreboard   Scoreboard     `xml:"scoreboard"`
+	Transactions struct {
+		Transaction []Transaction `xml:"transaction"`
+	} `xml:"transactions"`
+}
+
+type LeagueSettings struct {
+	DraftType        string `xml:"draft_type"`
+	ScoringType      string `xml:"scoring_type"`
+	UsesPlayoff      int    `xml:"uses_playoff"`
+	PlayoffStartWeek int    `xml:"playoff_start_week"`
+	NumPlayoffTeams  int    `xml:"num_playoff_teams"`
+	WaiverType       string `xml:"waiver_type"`
+	WaiverRule       string `xml:"waiver_rule"`
+	UsesFAAB         int    `xml:"uses_faab"`
+	TradeEndDate     string `xml:"trade_end_date"`
+	// WeeklyDeadline is set for leagues whose lineups lock once a week
+	WeeklyDeadline string `xml:"weekly_deadline"`
+	// MaxWeeklyAdds is empty or 0 in leagues without a weekly add limit
+	MaxWeeklyAdds   int             `xml:"max_weekly_adds"`
+	MinGamesPlayed  int             `xml:"min_games_played"`
+	RosterPositions RosterPositions `xml:"roster_positions"`
+	StatCategories  StatCategories  `xml:"stat_categories"`
+	StatModifiers   StatModifiers   `xml:"stat_modifiers"`
+}
+
+type RosterPositions struct {
+	RosterPosition []RosterPosition `xml:"roster_position"`
+}
+
+type RosterPosition struct {
+	Position           string `xml:"position"`
+	PositionType       string `xml:"position_type"`
+	Count              int    `xml:"count"`
+	IsStartingPosition int    `xml:"is_starting_position"`
+}
+
+type StatCategories struct {
+	Stats struct {
+		Stat []StatCategory `xml:"stat"`
+	} `xml:"stats"`
+}
+
+type StatCategory struct {
+	StatID      int    `xml:"stat_id"`
+	Enabled     int    `xml:"enabled"`
+	Name        string `xml:"name"`
+	DisplayName string `xml:"display_name"`
+	// SortOrder is 1 when higher values are better and 0 when lower are
+	SortOrder         int    `xml:"sort_order"`
+	PositionType      string `xml:"position_type"`
+	IsOnlyDisplayStat int    `xml:"is_only_display_stat"`
+}
+
+type StatModifiers struct {
+	Stats struct {
+		Stat []StatModifier `xml:"stat"`
+	} `xml:"stats"`
+}
+
+type StatModifier struct {
+	StatID int     `xml:"stat_id"`
+	Value  float64 `xml:"value"`
+}
+
+type Game struct {
+	GameKey   string    `xml:"game_key"`
+	Code      string    `xml:"code"`
+	Season    int       `xml:"season"`
+	GameWeeks GameWeeks `xml:"game_weeks"`
+}
+
+type GameWeeks struct {
+	GameWeek []GameWeek `xml:"game_week"`
+}
+
+type GameWeek struct {
+	Week  int    `xml:"week"`
+	Start string `xml:"start"`
+	End   string `xml:"end"`
 }
 
 type Team struct {
@@ -73,6 +224,56 @@ type Team struct {
 	HasDraftGrade  int        `xml:"has_draft_grade"`
 	Managers       Managers   `xml:"managers"`
 	Roster         Roster     `xml:"roster"`
+
+	TeamPoints          TeamPoints `xml:"team_points"`
+	TeamProjectedPoints TeamPoints `xml:"team_projected_points"`
+	TeamStats           TeamStats  `xml:"team_stats"`
+	Matchups            Matchups   `xml:"matchups"`
+}
+
+type TeamPoints struct {
+	CoverageType string  `xml:"coverage_type"`
+	Week         int     `xml:"week"`
+	Total        float64 `xml:"total"`
+}
+
+type TeamStats struct {
+	CoverageType string `xml:"coverage_type"`
+	Week         int    `xml:"week"`
+	Stats        struct {
+		Stat []Stat `xml:"stat"`
+	} `xml:"stats"`
+}
+
+type Scoreboard struct {
+	Week     int      `xml:"week"`
+	Matchups Matchups `xml:"matchups"`
+}
+
+type Matchups struct {
+	Matchup []Matchup `xml:"matchup"`
+}
+
+type Matchup struct {
+	Week          int    `xml:"week"`
+	WeekStart     string `xml:"week_start"`
+	WeekEnd       string `xml:"week_end"`
+	Status        string `xml:"status"`
+	IsPlayoffs    int    `xml:"is_playoffs"`
+	IsTied        int    `xml:"is_tied"`
+	WinnerTeamKey string `xml:"winner_team_key"`
+	StatWinners   struct {
+		StatWinner []StatWinner `xml:"stat_winner"`
+	} `xml:"stat_winners"`
+	Teams struct {
+		Team []Team `xml:"team"`
+	} `xml:"teams"`
+}
+
+type StatWinner struct {
+	StatID        int    `xml:"stat_id"`
+	WinnerTeamKey string `xml:"winner_team_key"`
+	IsTied        int    `xml:"is_tied"`
 }
 
 type TeamLogos struct {
@@ -108,6 +309,7 @@ type Manager struct {
 type Roster struct {
 	CoverageType string  `xml:"coverage_type"`
 	Date         string  `xml:"date"`
+	Week         int     `xml:"week"`
 	IsEditable   int     `xml:"is_editable"`
 	Players      Players `xml:"players"`
 }
@@ -122,6 +324,13 @@ type Player struct {
 	Name               Name              `xml:"name"`
 	URL                string            `xml:"url"`
 	EditorialPlayerKey string            `xml:"editorial_player_key"`
+	EditorialTeamKey   string            `xml:"editorial_team_key"`
+	EditorialTeamName  string            `xml:"editorial_team_full_name"`
+	EditorialTeamAbbr  string            `xml:"editorial_team_abbr"`
+	Status             string            `xml:"status"`
+	StatusFull         string            `xml:"status_full"`
+	InjuryNote         string            `xml:"injury_note"`
+	OnDisabledList     int               `xml:"on_disabled_list"`
 	IsKeeper           IsKeeper          `xml:"is_keeper"`
 	UniformNumber      int               `xml:"uniform_number"`
 	DisplayPosition    string            `xml:"display_position"`
@@ -133,6 +342,9 @@ type Player struct {
 	EligiblePositions  EligiblePositions `xml:"eligible_positions"`
 	SelectedPosition   SelectedPosition  `xml:"selected_position"`
 	IsEditable         int               `xml:"is_editable"`
+	PercentOwned       PercentOwned      `xml:"percent_owned"`
+	PlayerStats        PlayerStats       `xml:"player_stats"`
+	PlayerPoints       PlayerPoints      `xml:"player_points"`
 }
 
 type Name struct {
@@ -149,6 +361,34 @@ type IsKeeper struct {
 	Kept   string `xml:"kept"`
 }
 
+type PercentOwned struct {
+	CoverageType string  `xml:"coverage_type"`
+	Week         int     `xml:"week"`
+	Value        float64 `xml:"value"`
+	Delta        float64 `xml:"delta"`
+}
+
+type PlayerStats struct {
+	CoverageType string `xml:"coverage_type"`
+	Season       int    `xml:"season"`
+	Date         string `xml:"date"`
+	Week         int    `xml:"week"`
+	Stats        struct {
+		Stat []Stat `xml:"stat"`
+	} `xml:"stats"`
+}
+
+type Stat struct {
+	StatID int    `xml:"stat_id"`
+	Value  string `xml:"value"`
+}
+
+type PlayerPoints struct {
+	CoverageType string  `xml:"coverage_type"`
+	Season       int     `xml:"season"`
+	Total        float64 `xml:"total"`
+}
+
 type Headshot struct {
 	URL  string `xml:"url"`
 	Size string `xml:"size"`
@@ -161,6 +401,7 @@ type EligiblePositions struct {
 type SelectedPosition struct {
 	CoverageType string `xml:"coverage_type"`
 	Date         string `xml:"date"`
+	Week         int    `xml:"week"`
 	Position     string `xml:"position"`
 	IsFlex       int    `xml:"is_flex"`
 }
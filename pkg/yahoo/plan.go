@@ -0,0 +1,293 @@
+package yahoo
+
+import (
+	"encoding/json"
+	"fmt"
+	"hockey-hacks/pkg/playerMap"
+	"hockey-hacks/pkg/sportsData"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Move is a planned lineup change for a single player.
+type Move struct {
+	PlayerKey string `json:"player_key"`
+	Name      string `json:"name"`
+	Current   string `json:"current_position"`
+	Position  string `json:"new_position"`
+	Reason    string `json:"reason"`
+	// Basis says whether a goalie decision rests on a confirmed or only a
+	// projected start
+	Basis string `json:"basis,omitempty"`
+}
+
+// NewMove returns a move of the player to the given position.
+func NewMove(p Player, position string, reason string) Move {
+	return Move{
+		PlayerKey: p.PlayerKey,
+		Name:      p.Name.Full,
+		Current:   p.SelectedPosition.Position,
+		Position:  position,
+		Reason:    reason,
+	}
+}
+
+// Changes returns the moves that put a player in a different position.
+func Changes(moves []Move) []Move {
+	var changes []Move
+	for _, m := range moves {
+		if m.Position != m.Current {
+			changes = append(changes, m)
+		}
+	}
+	return changes
+}
+
+// Merge combines moves planned in several passes into one move per player,
+// from the original position to the last planned one.
+func Merge(moves []Move) []Move {
+	index := make(map[string]int)
+	var merged []Move
+	for _, m := range moves {
+		i, ok := index[m.PlayerKey]
+		if !ok {
+			index[m.PlayerKey] = len(merged)
+			merged = append(merged, m)
+			continue
+		}
+		m.Current = merged[i].Current
+		merged[i] = m
+	}
+	return merged
+}
+
+// Pickup is a free agent added to the roster and the player dropped to make
+// room.
+type Pickup struct {
+	Add            string `json:"add"`
+	AddName        string `json:"add_name"`
+	Drop           string `json:"drop"`
+	DropName       string `json:"drop_name"`
+	Reason         string `json:"reason"`
+	TransactionKey string `json:"transaction_key,omitempty"`
+	Status         string `json:"status,omitempty"`
+}
+
+// Plan is the set of lineup changes for a team on a date, along with the
+// error that stopped it if any.
+type Plan struct {
+	TeamKey string   `json:"team_key"`
+	Date    string   `json:"date"`
+	Week    int      `json:"week,omitempty"`
+	Pickups []Pickup `json:"pickups,omitempty"`
+	Moves   []Move   `json:"moves"`
+	// Warnings are moves that were refused, such as ones that would leave
+	// the roster illegal
+	Warnings []string `json:"warnings,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// WriteText writes the plan as a human-readable table.
+func (p Plan) WriteText(w io.Writer) error {
+	if p.Week != 0 {
+		fmt.Fprintf(w, "Lineup plan for %s for week %d\n", p.TeamKey, p.Week)
+	} else {
+		fmt.Fprintf(w, "Lineup plan for %s on %s\n", p.TeamKey, p.Date)
+	}
+	for _, pu := range p.Pickups {
+		fmt.Fprintf(w, "Add %s, drop %s (%s)", pu.AddName, pu.DropName, pu.Reason)
+		if pu.Status != "" {
+			fmt.Fprintf(w, ": %s", pu.Status)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, warning := range p.Warnings {
+		fmt.Fprintln(w, "Warning:", warning)
+	}
+	if p.Error != "" {
+		fmt.Fprintln(w, "Failed:", p.Error)
+		return nil
+	}
+	if len(p.Moves) == 0 {
+		fmt.Fprintln(w, "Lineup already set, no moves to make")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLAYER\tCURRENT\tNEW\tREASON\tBASIS")
+	for _, m := range p.Moves {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", m.Name, m.Current, m.Position, m.Reason, m.Basis)
+	}
+	return tw.Flush()
+}
+
+// Plans is the result of a run across several teams.
+type Plans []Plan
+
+// WriteText writes each plan as a human-readable table.
+func (ps Plans) WriteText(w io.Writer) error {
+	for i, p := range ps {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if err := p.WriteText(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the plans as an indented JSON array.
+func (ps Plans) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ps)
+}
+
+// NewSwapPlayerRequest builds the roster update for the given moves.
+func NewSwapPlayerRequest(coverage Coverage, moves []Move) SwapPlayerRequest {
+	var requestBody SwapPlayerRequest
+	requestBody.Roster.CoverageType = coverage.Type
+	if coverage.Type == CoverageWeek {
+		requestBody.Roster.Week = coverage.Week
+	} else {
+		requestBody.Roster.Date = coverage.Date.Format(time.DateOnly)
+	}
+	for _, m := range moves {
+		requestBody.Roster.Players.Player = append(requestBody.Roster.Players.Player, SwapPlayer{
+			PlayerKey: m.PlayerKey,
+			Position:  m.Position,
+		})
+	}
+	return requestBody
+}
+
+// PlanGoalies starts the rostered goalies who are projected to play in the
+// league's goalie slots and benches the rest, trusting unconfirmed
+// projections as far as the policy allows. Goalies whose team plays but who
+// are not starting are used to fill any goalie slots left open. Goalies
+// ranked alike are ordered by their projected points when projections are
+// given. Locked goalies, and goalies the policy holds until a start is
+// confirmed, keep their slot and the rest fill what is left.
+func PlanGoalies(roster Players, teamGoalies sportsData.Goalies, ids *playerMap.Map, settings LeagueSettings, policy StarterPolicy, projected Projected, locked map[string]bool) []Move {
+	// Check if we have no starting goalies
+	if len(teamGoalies) == 0 {
+		return nil
+	}
+
+	slots, position := settings.GoalieSlots(), settings.GoaliePosition()
+	var moves []Move
+	var candidates []Player
+	for _, p := range lineupGoalies(roster) {
+		if !locked[p.PlayerKey] {
+			candidates = append(candidates, p)
+			continue
+		}
+		if p.SelectedPosition.Position != PositionBench {
+			slots--
+		}
+		moves = append(moves, LockedMove(p))
+	}
+
+	decisions := make(map[string]starterDecision)
+	var open []Player
+	for _, p := range candidates {
+		d := decideStarter(p, teamGoalies, ids, policy)
+		if !d.hold {
+			decisions[p.PlayerKey] = d
+			open = append(open, p)
+			continue
+		}
+		if p.SelectedPosition.Position != PositionBench {
+			slots--
+		}
+		m := NewMove(p, p.SelectedPosition.Position, d.reason)
+		m.Basis = d.basis
+		moves = append(moves, m)
+	}
+	candidates = open
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := decisions[candidates[i].PlayerKey].rank, decisions[candidates[j].PlayerKey].rank
+		if ri != rj {
+			return ri > rj
+		}
+		vi, _ := projected.Value(candidates[i].PlayerKey)
+		vj, _ := projected.Value(candidates[j].PlayerKey)
+		return vi > vj
+	})
+
+	for i, p := range candidates {
+		d := decisions[p.PlayerKey]
+		m := NewMove(p, position, d.reason)
+		switch {
+		case d.rank == 0:
+			m.Position = PositionBench
+		case i >= slots && d.rank >= 2:
+			m.Position, m.Reason = PositionBench, "goalie slots full"
+		case i >= slots:
+			m.Position = PositionBench
+		}
+		m.Reason = projected.Describe(p.PlayerKey, m.Reason)
+		m.Basis = d.basis
+		moves = append(moves, m)
+	}
+	return moves
+}
+
+// PlanWeeklyGoalies picks the goalies for a weekly lineup from the week's
+// schedule and the starters projected so far, starting those with the most
+// expected starts. A team game counts as half a start and a projected
+// start counts in full.
+func PlanWeeklyGoalies(roster Players, schedules []sportsData.Games, days []sportsData.Goalies, ids *playerMap.Map, settings LeagueSettings) []Move {
+	candidates := lineupGoalies(roster)
+	slots, position := settings.GoalieSlots(), settings.GoaliePosition()
+
+	starts := make(map[string]int)
+	for _, teamGoalies := range days {
+		for _, goalie := range teamGoalies {
+			if key, ok := ids.YahooKey(goalie.PlayerID); ok {
+				starts[key]++
+			}
+		}
+	}
+	games := make(map[string]int)
+	for _, schedule := range schedules {
+		playing := schedule.Teams()
+		for _, p := range candidates {
+			if playing[p.Team()] {
+				games[p.PlayerKey]++
+			}
+		}
+	}
+
+	score := func(p Player) float64 {
+		return float64(starts[p.PlayerKey]) + 0.5*float64(games[p.PlayerKey]-starts[p.PlayerKey])
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return score(candidates[i]) > score(candidates[j])
+	})
+
+	var moves []Move
+	for i, p := range candidates {
+		reason := fmt.Sprintf("%d projected starts in %d team games", starts[p.PlayerKey], games[p.PlayerKey])
+		if i < slots && score(p) > 0 {
+			moves = append(moves, NewMove(p, position, reason))
+		} else {
+			moves = append(moves, NewMove(p, PositionBench, reason))
+		}
+	}
+	return moves
+}
+
+// lineupGoalies returns the goalies whose position can be changed, leaving
+// out those in injured reserve slots.
+func lineupGoalies(roster Players) []Player {
+	var candidates []Player
+	for _, p := range roster.Goalies() {
+		if !p.IsInjuredReserve() {
+			candidates = append(candidates, p)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,164 @@
+package yahoo_test
+
+import (
+	"hockey-hacks/pkg/playerMap"
+	"hockey-hacks/pkg/sportsData"
+	"hockey-hacks/pkg/yahoo"
+	"path/filepath"
+	"testing"
+)
+
+func goalie(key, name, team string) yahoo.Player {
+	p := yahoo.Player{PlayerKey: key, EditorialTeamAbbr: team, PrimaryPosition: yahoo.PositionGoalie}
+	p.Name.Full = name
+	p.SelectedPosition.Position = yahoo.PositionBench
+	return p
+}
+
+func TestPlanGoaliesBothStartsConfirmedFirst(t *testing.T) {
+	roster := yahoo.Players{PlayerList: []yahoo.Player{
+		goalie("465.p.7191", "Jeremy Swayman", "Bos"),
+		goalie("465.p.4718", "Joonas Korpisalo", "Bos"),
+		goalie("465.p.8640", "Joseph Woll", "Tor"),
+	}}
+	ids, err := playerMap.Load(filepath.Join(t.TempDir(), "player_map.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids.Set(playerMap.Entry{SportsDataID: 30005512, YahooKey: "465.p.7191"})
+	ids.Set(playerMap.Entry{SportsDataID: 30004567, YahooKey: "465.p.8640"})
+	starters := sportsData.Goalies{
+		{PlayerID: 30005512, Team: "BOS", Confirmed: false},
+		{PlayerID: 30004567, Team: "TOR", Confirmed: true},
+	}
+
+	// Both BOS goalies start under the both policy, but not at the cost of
+	// the confirmed TOR starter.
+	moves := yahoo.PlanGoalies(roster, starters, ids, yahoo.LeagueSettings{}, yahoo.StarterBoth, nil, nil)
+	got := make(map[string]string)
+	for _, m := range moves {
+		got[m.Name] = m.Position
+	}
+	if got["Joseph Woll"] != yahoo.PositionGoalie {
+		t.Errorf("confirmed starter Woll moved to %q, want %s: %v", got["Joseph Woll"], yahoo.PositionGoalie, moves)
+	}
+	if got["Jeremy Swayman"] != yahoo.PositionGoalie {
+		t.Errorf("projected starter Swayman moved to %q, want %s", got["Jeremy Swayman"], yahoo.PositionGoalie)
+	}
+	if got["Joonas Korpisalo"] != yahoo.PositionBench {
+		t.Errorf("Korpisalo moved to %q, want %s", got["Joonas Korpisalo"], yahoo.PositionBench)
+	}
+}
+
+func TestPlanWeeklyGoaliesCountsScheduledGames(t *testing.T) {
+	roster := yahoo.Players{PlayerList: []yahoo.Player{
+		goalie("465.p.4718", "Joonas Korpisalo", "Bos"),
+		goalie("465.p.7191", "Jeremy Swayman", "Bos"),
+		goalie("465.p.8640", "Joseph Woll", "Tor"),
+	}}
+	ids, err := playerMap.Load(filepath.Join(t.TempDir(), "player_map.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids.Set(playerMap.Entry{SportsDataID: 30005512, YahooKey: "465.p.7191"})
+
+	// TOR plays three times and BOS twice, but starters are only projected
+	// for the first day.
+	schedules := []sportsData.Games{
+		{{HomeTeam: "TOR", AwayTeam: "BOS"}},
+		{{HomeTeam: "TOR", AwayTeam: "NYR"}},
+		{{HomeTeam: "BOS", AwayTeam: "TOR"}},
+	}
+	days := []sportsData.Goalies{{{PlayerID: 30005512, Team: "BOS"}}, nil, nil}
+
+	moves := yahoo.PlanWeeklyGoalies(roster, schedules, days, ids, yahoo.LeagueSettings{})
+	want := map[string]string{
+		"Joseph Woll":      yahoo.PositionGoalie,
+		"Jeremy Swayman":   yahoo.PositionGoalie,
+		"Joonas Korpisalo": yahoo.PositionBench,
+	}
+	for _, m := range moves {
+		if m.Position != want[m.Name] {
+			t.Errorf("%s moved to %q, want %q (%s)", m.Name, m.Position, want[m.Name], m.Reason)
+		}
+	}
+}
+
+// positions maps each move's player name to its new position.
+func positions(moves []yahoo.Move) map[string]string {
+	got := make(map[string]string)
+	for _, m := range moves {
+		got[m.Name] = m.Position
+	}
+	return got
+}
+
+func TestPlanGoaliesTwoGoaliesPerTeam(t *testing.T) {
+	roster := yahoo.Players{PlayerList: []yahoo.Player{
+		goalie("465.p.4718", "Joonas Korpisalo", "Bos"),
+		goalie("465.p.7191", "Jeremy Swayman", "Bos"),
+		goalie("465.p.5734", "Anthony Stolarz", "Tor"),
+		goalie("465.p.8640", "Joseph Woll", "Tor"),
+	}}
+	// Korpisalo starts the day in a goalie slot
+	roster.PlayerList[0].SelectedPosition.Position = yahoo.PositionGoalie
+	ids, err := playerMap.Load(filepath.Join(t.TempDir(), "player_map.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids.Set(playerMap.Entry{SportsDataID: 30005512, YahooKey: "465.p.7191"})
+	ids.Set(playerMap.Entry{SportsDataID: 30004567, YahooKey: "465.p.8640"})
+
+	tests := []struct {
+		name         string
+		policy       yahoo.StarterPolicy
+		bosConfirmed bool
+		want         map[string]string
+	}{
+		{
+			name:   "projected starts one goalie per team",
+			policy: yahoo.StarterProjected,
+			want: map[string]string{
+				"Jeremy Swayman":   yahoo.PositionGoalie,
+				"Joseph Woll":      yahoo.PositionGoalie,
+				"Joonas Korpisalo": yahoo.PositionBench,
+				"Anthony Stolarz":  yahoo.PositionBench,
+			},
+		},
+		{
+			name:   "confirmed waits while no start is confirmed",
+			policy: yahoo.StarterConfirmed,
+			want: map[string]string{
+				"Jeremy Swayman":   yahoo.PositionBench,
+				"Joseph Woll":      yahoo.PositionBench,
+				"Joonas Korpisalo": yahoo.PositionGoalie,
+				"Anthony Stolarz":  yahoo.PositionBench,
+			},
+		},
+		{
+			name:         "confirmed starts a confirmed starter over the backup",
+			policy:       yahoo.StarterConfirmed,
+			bosConfirmed: true,
+			// Korpisalo may fill the slot the waiting TOR goalies leave open
+			want: map[string]string{
+				"Jeremy Swayman":  yahoo.PositionGoalie,
+				"Joseph Woll":     yahoo.PositionBench,
+				"Anthony Stolarz": yahoo.PositionBench,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			starters := sportsData.Goalies{
+				{PlayerID: 30005512, Team: "BOS", Confirmed: tt.bosConfirmed},
+				{PlayerID: 30004567, Team: "TOR"},
+			}
+			got := positions(yahoo.PlanGoalies(roster, starters, ids, yahoo.LeagueSettings{}, tt.policy, nil, nil))
+			for name, pos := range tt.want {
+				if got[name] != pos {
+					t.Errorf("%s moved to %q, want %q", name, got[name], pos)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package yahoo
+
+import (
+	"encoding/xml"
+	"hockey-hacks/pkg/email"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Player statuses to search by
+	StatusAvailable = "A"
+	StatusFreeAgent = "FA"
+	StatusWaivers   = "W"
+	StatusTaken     = "T"
+	StatusKeeper    = "K"
+
+	// Orders Yahoo can sort players by. Stats are sorted by their stat ID.
+	SortActualRank  = "AR"
+	SortOverallRank = "OR"
+	SortPoints      = "PTS"
+	SortName        = "NAME"
+	// SortOwnership orders the returned players by ownership, most owned
+	// first. Yahoo cannot sort by it, so the players are fetched in rank
+	// order and sorted afterwards: with a Count only the best ranked
+	// players are ordered, not the most owned ones of the whole league.
+	SortOwnership = "OWN"
+
+	// Periods to sort by and report stats for
+	StatsSeason    = "season"
+	StatsLastWeek  = "lastweek"
+	StatsLastMonth = "lastmonth"
+
+	// pageSize is the most players Yahoo returns per request
+	pageSize = 25
+)
+
+// PlayerSearch filters and orders a search of the league's players. Empty
+// fields are left out of the query.
+type PlayerSearch struct {
+	Status   string
+	Position string
+	// Search matches player names
+	Search string
+	Sort   string
+	// StatsType is the period stats are sorted by and returned for
+	StatsType string
+	Start     int
+	// Count is the number of players to return, 0 for every match
+	Count int
+}
+
+// filter returns the players collection filters for a page of the search.
+func (q PlayerSearch) filter(start int, count int) string {
+	var b strings.Builder
+	add := func(key, value string) {
+		if value != "" {
+			b.WriteString(";" + key + "=" + value)
+		}
+	}
+	add("status", q.Status)
+	add("position", q.Position)
+	add("search", url.PathEscape(q.Search))
+	switch q.Sort {
+	case SortOwnership:
+		add("sort", SortActualRank)
+	default:
+		add("sort", q.Sort)
+	}
+	if q.Sort != "" {
+		add("sort_type", q.StatsType)
+	}
+	add("start", strconv.Itoa(start))
+	add("count", strconv.Itoa(count))
+	return b.String()
+}
+
+// SearchPlayers returns the league's players matching the search with
+// their ownership and stats, fetching as many pages as needed.
+func (yc *YahooClient) SearchPlayers(leagueKey string, q PlayerSearch) (Players, error) {
+	var found Players
+	for start := q.Start; q.Count == 0 || len(found.PlayerList) < q.Count; start += pageSize {
+		count := pageSize
+		if q.Count != 0 && q.Count-len(found.PlayerList) < count {
+			count = q.Count - len(found.PlayerList)
+		}
+		page, err := yc.getLeaguePlayers(leagueKey, q.filter(start, count), q.StatsType)
+		if err != nil {
+			return Players{}, err
+		}
+		found.PlayerList = append(found.PlayerList, page.PlayerList...)
+		if len(page.PlayerList) < count {
+			break
+		}
+	}
+
+	if q.Sort == SortOwnership {
+		sort.SliceStable(found.PlayerList, func(i, j int) bool {
+			return found.PlayerList[i].PercentOwned.Value > found.PlayerList[j].PercentOwned.Value
+		})
+	}
+	return found, nil
+}
+
+// GetFreeAgents returns the league's free agents at the position, best
+// ranked first, with their ownership.
+func (yc *YahooClient) GetFreeAgents(leagueKey string, position string) (Players, error) {
+	return yc.SearchPlayers(leagueKey, PlayerSearch{
+		Status:   StatusFreeAgent,
+		Position: position,
+		Sort:     SortActualRank,
+	})
+}
+
+// GetPlayers returns the given players with their ownership in the league.
+func (yc *YahooClient) GetPlayers(leagueKey string, playerKeys []string) (Players, error) {
+	return yc.getLeaguePlayers(leagueKey, ";player_keys="+strings.Join(playerKeys, ","), "")
+}
+
+// getLeaguePlayers returns a page of league players with their ownership,
+// and their stats for the period when one is given.
+func (yc *YahooClient) getLeaguePlayers(leagueKey string, filter string, statsType string) (Players, error) {
+	url := yc.BaseURL + "/league/" + leagueKey + "/players" + filter
+	if statsType != "" {
+		url += ";out=percent_owned/stats;type=" + statsType
+	} else {
+		url += ";out=percent_owned,stats"
+	}
+	respBody, err := yc.sendXMLRequest(http.MethodGet, url, nil)
+	if err != nil {
+		email.Notify(yc.EnableEmail, respBody)
+		log.Println("Failed to get league players:", err)
+		return Players{}, err
+	}
+
+	var fantasyContent FantasyContent
+	if err := xml.Unmarshal(respBody, &fantasyContent); err != nil {
+		log.Println("Error unmarshaling XML:", err)
+		return Players{}, err
+	}
+	return fantasyContent.League.Players, nil
+}
+
+// Stat returns the player's value for the stat, if it has one.
+func (p Player) Stat(statID int) (float64, bool) {
+	return statValue(p.PlayerStats.Stats.Stat, statID)
+}
+
+func statValue(stats []Stat, statID int) (float64, bool) {
+	for _, s := range stats {
+		if s.StatID == statID {
+			v, err := strconv.ParseFloat(s.Value, 64)
+			return v, err == nil
+		}
+	}
+	return 0, false
+}
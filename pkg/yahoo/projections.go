@@ -0,0 +1,65 @@
+package yahoo
+
+import (
+	"fmt"
+	"hockey-hacks/pkg/playerMap"
+	"hockey-hacks/pkg/sportsData"
+	"strings"
+)
+
+// Projected holds the day's projected stat lines of rostered players, keyed
+// by Yahoo player key.
+type Projected map[string]sportsData.Player
+
+// RosterProjections matches the day's projections to the rostered players
+// through the player map. Only projections on a rostered player's NHL team
+// with the same last name are matched, so other players are never mapped by
+// mistake.
+func RosterProjections(roster Players, projections sportsData.Projections, ids *playerMap.Map) Projected {
+	rosterKeys := make(map[string]bool)
+	lastNames := make(map[string]bool)
+	for _, p := range roster.PlayerList {
+		rosterKeys[p.PlayerKey] = true
+		c := p.Candidate()
+		lastNames[c.Team+" "+strings.ToLower(c.Last)] = true
+	}
+	candidates := Candidates(roster.PlayerList)
+
+	projected := make(Projected)
+	for id, proj := range projections {
+		key, ok := ids.YahooKey(id)
+		if !ok {
+			first, last := proj.FirstLast()
+			team := sportsData.TeamAbbr(proj.Team)
+			if !lastNames[team+" "+strings.ToLower(last)] {
+				continue
+			}
+			if key, ok = ids.Resolve(id, first, last, team, candidates); !ok {
+				continue
+			}
+		}
+		if rosterKeys[key] {
+			projected[key] = proj
+		}
+	}
+	return projected
+}
+
+// Value returns the player's projected Yahoo fantasy points, if there is a
+// projection.
+func (pr Projected) Value(playerKey string) (float64, bool) {
+	p, ok := pr[playerKey]
+	return p.FantasyPointsYahoo, ok
+}
+
+// Describe adds the player's projection to a move reason.
+func (pr Projected) Describe(playerKey string, reason string) string {
+	p, ok := pr[playerKey]
+	if !ok {
+		return reason
+	}
+	if p.IsGoalie() {
+		return fmt.Sprintf("%s, %.1f projected pts, %.0f%% win chance", reason, p.FantasyPointsYahoo, 100*p.WinProbability())
+	}
+	return fmt.Sprintf("%s, %.1f projected pts", reason, p.FantasyPointsYahoo)
+}
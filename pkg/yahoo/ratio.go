@@ -0,0 +1,185 @@
+package yahoo
+
+import (
+	"fmt"
+	"log"
+)
+
+const (
+	// Goalie stat IDs
+	StatGoalieStarts    = 18
+	StatWins            = 19
+	StatGoalsAgainst    = 22
+	StatGoalsAgainstAvg = 23
+	StatShotsAgainst    = 24
+	StatSaves           = 25
+	StatSavePercentage  = 26
+	StatShutouts        = 27
+
+	// ScoringHeadToHead is the scoring type of head-to-head category leagues
+	ScoringHeadToHead = "head"
+
+	defaultBadStartGA    = 4
+	defaultBadStartShots = 30
+)
+
+// ratioStatNames names the stats the ratio check reads from a matchup.
+var ratioStatNames = map[int]string{
+	StatGoalsAgainst:    "GA",
+	StatGoalsAgainstAvg: "GAA",
+	StatShotsAgainst:    "SA",
+	StatSaves:           "SV",
+	StatSavePercentage:  "SV%",
+}
+
+// RatioRisk configures when goalies are benched to protect GAA and SV%
+// leads in head-to-head category leagues.
+type RatioRisk struct {
+	// BadStartGoalsAgainst and BadStartShots describe the start to guard
+	// against, 4 goals on 30 shots by default
+	BadStartGoalsAgainst int
+	BadStartShots        int
+	// CategoriesAtRisk is how many ratio leads a bad start must cost
+	// before goalies are benched, 1 by default
+	CategoriesAtRisk int
+	// MinGames overrides the league's minimum goalie games, 0 to use it
+	MinGames int
+}
+
+// RatioDecision is the outcome of a ratio protection check with the
+// numbers behind it.
+type RatioDecision struct {
+	Bench  bool
+	Reason string
+}
+
+// ratio is one ratio category of the matchup before and after a bad start.
+type ratio struct {
+	name   string
+	ours   float64
+	theirs float64
+	after  float64
+	// lower is true for GAA, where lower values are better
+	lower bool
+}
+
+func (r ratio) leads(ours float64) bool {
+	if r.lower {
+		return ours < r.theirs
+	}
+	return ours > r.theirs
+}
+
+// CheckRatios decides whether starting another goalie puts the team's ratio
+// category leads at risk. Goalies are only benched in category leagues that
+// score GAA or SV%, once the team has played the minimum goalie games, and
+// when a bad start would cost at least CategoriesAtRisk of the leads.
+// Goalies are not benched when the matchup lacks a stat the check needs.
+func CheckRatios(m Matchup, teamKey string, settings LeagueSettings, risk RatioRisk) (RatioDecision, error) {
+	if risk.BadStartGoalsAgainst == 0 {
+		risk.BadStartGoalsAgainst = defaultBadStartGA
+	}
+	if risk.BadStartShots == 0 {
+		risk.BadStartShots = defaultBadStartShots
+	}
+	if risk.CategoriesAtRisk == 0 {
+		risk.CategoriesAtRisk = 1
+	}
+	minGames := risk.MinGames
+	if minGames == 0 {
+		minGames = settings.MinGamesPlayed
+	}
+
+	_, scoresGAA := settings.Category(StatGoalsAgainstAvg)
+	_, scoresSV := settings.Category(StatSavePercentage)
+	if settings.ScoringType != ScoringHeadToHead || (!scoresGAA && !scoresSV) {
+		return RatioDecision{Reason: "not a category league scoring goalie ratios"}, nil
+	}
+
+	us, them, err := m.Sides(teamKey)
+	if err != nil {
+		return RatioDecision{}, err
+	}
+
+	_, hasStarts := us.Stat(StatGoalieStarts)
+	var ours, theirs []int
+	if scoresGAA || !hasStarts {
+		ours = append(ours, StatGoalsAgainst, StatGoalsAgainstAvg)
+	}
+	if scoresGAA {
+		theirs = append(theirs, StatGoalsAgainstAvg)
+	}
+	if scoresSV {
+		ours = append(ours, StatShotsAgainst, StatSaves, StatSavePercentage)
+		theirs = append(theirs, StatSavePercentage)
+	}
+	for _, id := range ours {
+		if _, ok := us.Stat(id); !ok {
+			return RatioDecision{Reason: fmt.Sprintf("matchup is missing our %s", ratioStatNames[id])}, nil
+		}
+	}
+	for _, id := range theirs {
+		if _, ok := them.Stat(id); !ok {
+			return RatioDecision{Reason: fmt.Sprintf("matchup is missing the opponent's %s", ratioStatNames[id])}, nil
+		}
+	}
+
+	ga, _ := us.Stat(StatGoalsAgainst)
+	gaa, _ := us.Stat(StatGoalsAgainstAvg)
+	sa, _ := us.Stat(StatShotsAgainst)
+	sv, _ := us.Stat(StatSaves)
+	// Yahoo does not report time on ice, so it is worked out from GA and GAA
+	minutes := 0.0
+	if gaa > 0 {
+		minutes = ga * 60 / gaa
+	}
+	games, ok := us.Stat(StatGoalieStarts)
+	if !ok {
+		games = float64(int(minutes/60 + 0.5))
+	}
+	if games < float64(minGames) {
+		return RatioDecision{Reason: fmt.Sprintf("%.0f of %d minimum goalie games played", games, minGames)}, nil
+	}
+
+	badGA, badShots := float64(risk.BadStartGoalsAgainst), float64(risk.BadStartShots)
+	var ratios []ratio
+	if scoresGAA {
+		theirs, _ := them.Stat(StatGoalsAgainstAvg)
+		ratios = append(ratios, ratio{name: "GAA", ours: gaa, theirs: theirs, after: (ga + badGA) * 60 / (minutes + 60), lower: true})
+	}
+	if scoresSV {
+		ours, _ := us.Stat(StatSavePercentage)
+		theirs, _ := them.Stat(StatSavePercentage)
+		ratios = append(ratios, ratio{name: "SV%", ours: ours, theirs: theirs, after: (sv + badShots - badGA) / (sa + badShots)})
+	}
+
+	atRisk := 0
+	reason := fmt.Sprintf("%.0f goalie games, after %d GA on %d shots:", games, risk.BadStartGoalsAgainst, risk.BadStartShots)
+	for _, r := range ratios {
+		reason += fmt.Sprintf(" %s %.3f vs %.3f -> %.3f", r.name, r.ours, r.theirs, r.after)
+		if r.leads(r.ours) && !r.leads(r.after) {
+			atRisk++
+			reason += " (lead lost)"
+		}
+	}
+	return RatioDecision{Bench: atRisk >= risk.CategoriesAtRisk, Reason: reason}, nil
+}
+
+// ProtectRatios benches the goalies the moves would start when the decision
+// says so, leaving locked goalies alone, and logs the decision either way.
+func ProtectRatios(moves []Move, teamKey string, decision RatioDecision, locked map[string]bool) []Move {
+	if !decision.Bench {
+		log.Printf("%s: Starting goalies, ratios not at risk: %s", teamKey, decision.Reason)
+		return moves
+	}
+	log.Printf("%s: Benching goalies to protect ratios: %s", teamKey, decision.Reason)
+	protected := make([]Move, len(moves))
+	for i, m := range moves {
+		if m.Position != PositionBench && !locked[m.PlayerKey] {
+			m.Position = PositionBench
+			m.Reason = "protecting ratios: " + decision.Reason
+		}
+		protected[i] = m
+	}
+	return protected
+}
@@ -0,0 +1,59 @@
+package yahoo_test
+
+import (
+	"hockey-hacks/pkg/yahoo"
+	"strings"
+	"testing"
+)
+
+const ourKey = "465.l.1234.t.8"
+
+func matchupTeam(key string, stats map[int]string) yahoo.Team {
+	t := yahoo.Team{TeamKey: key}
+	for id, v := range stats {
+		t.TeamStats.Stats.Stat = append(t.TeamStats.Stats.Stat, yahoo.Stat{StatID: id, Value: v})
+	}
+	return t
+}
+
+func TestCheckRatiosMissingStat(t *testing.T) {
+	var settings yahoo.LeagueSettings
+	settings.ScoringType = yahoo.ScoringHeadToHead
+	for _, id := range []int{yahoo.StatGoalsAgainstAvg, yahoo.StatSavePercentage} {
+		settings.StatCategories.Stats.Stat = append(settings.StatCategories.Stats.Stat, yahoo.StatCategory{StatID: id, Enabled: 1})
+	}
+	them := matchupTeam("465.l.1234.t.2", map[int]string{yahoo.StatGoalsAgainstAvg: "3.10", yahoo.StatSavePercentage: ".900"})
+
+	full := map[int]string{
+		yahoo.StatGoalieStarts:    "4",
+		yahoo.StatGoalsAgainst:    "8",
+		yahoo.StatGoalsAgainstAvg: "2.10",
+		yahoo.StatShotsAgainst:    "120",
+		yahoo.StatSaves:           "112",
+		yahoo.StatSavePercentage:  ".933",
+	}
+	var m yahoo.Matchup
+	m.Teams.Team = []yahoo.Team{matchupTeam(ourKey, full), them}
+	if d, err := yahoo.CheckRatios(m, ourKey, settings, yahoo.RatioRisk{BadStartGoalsAgainst: 10}); err != nil || !d.Bench {
+		t.Fatalf("all stats present: got %+v, %v, want bench", d, err)
+	}
+
+	for _, missing := range []int{yahoo.StatGoalsAgainst, yahoo.StatShotsAgainst, yahoo.StatSaves, yahoo.StatSavePercentage} {
+		stats := make(map[int]string)
+		for id, v := range full {
+			stats[id] = v
+		}
+		// Yahoo reports stats without a value as "-"
+		stats[missing] = "-"
+		var m yahoo.Matchup
+		m.Teams.Team = []yahoo.Team{matchupTeam(ourKey, stats), them}
+
+		d, err := yahoo.CheckRatios(m, ourKey, settings, yahoo.RatioRisk{BadStartGoalsAgainst: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Bench || !strings.Contains(d.Reason, "missing") {
+			t.Errorf("stat %d missing: got %+v, want no bench with the missing stat named", missing, d)
+		}
+	}
+}
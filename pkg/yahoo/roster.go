@@ -0,0 +1,82 @@
+package yahoo
+
+import (
+	"hockey-hacks/pkg/playerMap"
+	"hockey-hacks/pkg/sportsData"
+)
+
+// Team returns the player's NHL team in SportsData.io format.
+func (p Player) Team() string {
+	return sportsData.TeamAbbr(p.EditorialTeamAbbr)
+}
+
+// IsEligible reports whether the player may be placed in the given slot.
+func (p Player) IsEligible(position string) bool {
+	for _, pos := range p.EligiblePositions.Positions {
+		if pos == position {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGoalie reports whether the player is a goalie.
+func (p Player) IsGoalie() bool {
+	return p.PrimaryPosition == PositionGoalie || p.IsEligible(PositionGoalie)
+}
+
+// IsInjuredReserve reports whether the player currently occupies an
+// injured or not-active slot that lineup changes must leave alone.
+func (p Player) IsInjuredReserve() bool {
+	return isIRPosition(p.SelectedPosition.Position)
+}
+
+// IsDroppable reports whether the player may be dropped: Yahoo marks some
+// players undroppable, and keepers are never dropped.
+func (p Player) IsDroppable() bool {
+	return p.IsUndroppable == 0 && p.IsKeeper.Kept != "1" && p.IsKeeper.Kept != "true"
+}
+
+// Candidate returns the player as a player map match candidate.
+func (p Player) Candidate() playerMap.Candidate {
+	first, last := p.Name.AsciiFirst, p.Name.AsciiLast
+	if first == "" && last == "" {
+		first, last = p.Name.First, p.Name.Last
+	}
+	return playerMap.Candidate{Key: p.PlayerKey, First: first, Last: last, Team: p.Team()}
+}
+
+// Candidates returns the given players as player map match candidates.
+func Candidates(players []Player) []playerMap.Candidate {
+	var candidates []playerMap.Candidate
+	for _, p := range players {
+		candidates = append(candidates, p.Candidate())
+	}
+	return candidates
+}
+
+// Goalies returns the goalies on the roster.
+func (ps Players) Goalies() []Player {
+	var goalies []Player
+	for _, p := range ps.PlayerList {
+		if p.IsGoalie() {
+			goalies = append(goalies, p)
+		}
+	}
+	return goalies
+}
+
+// Teams returns the distinct NHL teams represented by the given players.
+func Teams(players []Player) []string {
+	seen := make(map[string]bool)
+	var teams []string
+	for _, p := range players {
+		team := p.Team()
+		if team == "" || seen[team] {
+			continue
+		}
+		seen[team] = true
+		teams = append(teams, team)
+	}
+	return teams
+}
@@ -0,0 +1,86 @@
+package yahoo
+
+import "strings"
+
+const (
+	// Roster position types
+	PositionTypeSkater = "P"
+	PositionTypeGoalie = "G"
+
+	// Lineup lock types
+	LockGameTime = "game time"
+	LockDaily    = "daily"
+	LockWeekly   = "weekly"
+)
+
+// StartingPositions returns the league's active lineup positions of the
+// position type, e.g. PositionTypeGoalie.
+func (s LeagueSettings) StartingPositions(positionType string) []RosterPosition {
+	var positions []RosterPosition
+	for _, rp := range s.RosterPositions.RosterPosition {
+		if rp.IsStartingPosition == 1 && rp.PositionType == positionType {
+			positions = append(positions, rp)
+		}
+	}
+	return positions
+}
+
+// GoalieSlots returns the number of active goalie slots, falling back to
+// DefaultGoalieSlots when the settings list no roster positions.
+func (s LeagueSettings) GoalieSlots() int {
+	if len(s.RosterPositions.RosterPosition) == 0 {
+		return DefaultGoalieSlots
+	}
+	slots := 0
+	for _, rp := range s.StartingPositions(PositionTypeGoalie) {
+		slots += rp.Count
+	}
+	return slots
+}
+
+// GoaliePosition returns the lineup position goalies are started in.
+func (s LeagueSettings) GoaliePosition() string {
+	if positions := s.StartingPositions(PositionTypeGoalie); len(positions) > 0 {
+		return positions[0].Position
+	}
+	return PositionGoalie
+}
+
+// LockType returns when lineups lock: at each game's start, once a day or
+// once a week.
+func (s LeagueSettings) LockType() string {
+	switch strings.ToLower(s.WeeklyDeadline) {
+	case "intraday":
+		return LockGameTime
+	case "":
+		return LockDaily
+	}
+	return LockWeekly
+}
+
+// IsPlayoffWeek reports whether the fantasy week is part of the playoffs.
+func (s LeagueSettings) IsPlayoffWeek(week int) bool {
+	return s.UsesPlayoff == 1 && s.PlayoffStartWeek > 0 && week >= s.PlayoffStartWeek
+}
+
+// Category returns the league's scoring category for the stat, if it
+// scores it.
+func (s LeagueSettings) Category(statID int) (StatCategory, bool) {
+	for _, c := range s.StatCategories.Stats.Stat {
+		if c.StatID == statID && c.Enabled == 1 && c.IsOnlyDisplayStat == 0 {
+			return c, true
+		}
+	}
+	return StatCategory{}, false
+}
+
+// Modifier returns the fantasy points the league awards per unit of the
+// stat, 0 for stats that do not score.
+func (s LeagueSettings) Modifier(statID int) float64 {
+	for _, m := range s.StatModifiers.Stats.Stat {
+		if m.StatID == statID {
+			return m.Value
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package yahoo
+
+import (
+	"fmt"
+	"hockey-hacks/pkg/playerMap"
+	"hockey-hacks/pkg/sportsData"
+)
+
+// StarterPolicy decides how much to trust a starting goalie projection that
+// is not confirmed yet. Every run plans the lineup from the latest
+// projections, so a decision made on a projection is revisited by the next
+// scheduled run and upgraded once the start is confirmed.
+type StarterPolicy string
+
+const (
+	// StarterConfirmed only moves goalies on confirmed starts. The goalies
+	// of a team whose projected starter is not confirmed yet stay where
+	// they are until the confirmation arrives.
+	StarterConfirmed StarterPolicy = "confirmed"
+	// StarterProjected starts projected starters whether or not they are
+	// confirmed and re-checks them on later runs. This is the default.
+	StarterProjected StarterPolicy = "projected"
+	// StarterBoth starts every rostered goalie of a team whose starter is
+	// not confirmed yet, so the start is not missed either way.
+	StarterBoth StarterPolicy = "both"
+
+	// Decision bases reported with each goalie move
+	BasisConfirmed = "confirmed"
+	BasisProjected = "projected"
+)
+
+// ParseStarterPolicy returns the policy by name, the default when empty.
+func ParseStarterPolicy(name string) (StarterPolicy, error) {
+	switch p := StarterPolicy(name); p {
+	case "":
+		return StarterProjected, nil
+	case StarterConfirmed, StarterProjected, StarterBoth:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown starter policy %q, want %s, %s or %s", name, StarterConfirmed, StarterProjected, StarterBoth)
+}
+
+// starterDecision is how a rostered goalie ranks for a start under the
+// policy and what data the ranking rests on.
+type starterDecision struct {
+	// rank is 3 for a confirmed starter, 2 for one the policy trusts
+	// unconfirmed, 1 when only the goalie's team is known to play and 0
+	// when it does not
+	rank int
+	// hold keeps the goalie in its current slot while the policy waits for
+	// a confirmation
+	hold   bool
+	reason string
+	basis  string
+}
+
+// decideStarter ranks a rostered goalie against the projected starters of
+// the teams that play.
+func decideStarter(p Player, teamGoalies sportsData.Goalies, ids *playerMap.Map, policy StarterPolicy) starterDecision {
+	var teamStarter *sportsData.Goalie
+	for i, goalie := range teamGoalies {
+		if key, ok := ids.YahooKey(goalie.PlayerID); ok && key == p.PlayerKey {
+			switch {
+			case goalie.Confirmed:
+				return starterDecision{rank: 3, reason: "confirmed starter", basis: BasisConfirmed}
+			case policy == StarterConfirmed:
+				return starterDecision{hold: true, reason: "projected starter, waiting for confirmation", basis: BasisProjected}
+			}
+			return starterDecision{rank: 2, reason: "projected starter", basis: BasisProjected}
+		}
+		if goalie.Team == p.Team() {
+			teamStarter = &teamGoalies[i]
+		}
+	}
+	switch {
+	case teamStarter == nil:
+		return starterDecision{reason: "team not playing"}
+	case teamStarter.Confirmed:
+		return starterDecision{rank: 1, reason: "team plays, another goalie confirmed", basis: BasisConfirmed}
+	case policy == StarterConfirmed:
+		return starterDecision{hold: true, reason: "team's starter not confirmed, waiting", basis: BasisProjected}
+	case policy == StarterBoth:
+		return starterDecision{rank: 2, reason: "team's starter not confirmed, starting both", basis: BasisProjected}
+	}
+	return starterDecision{rank: 1, reason: "team plays, not projected starter", basis: BasisProjected}
+}
@@ -0,0 +1,152 @@
+package yahoo
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hockey-hacks/pkg/audit"
+	"hockey-hacks/pkg/clock"
+	"hockey-hacks/pkg/email"
+	"log"
+	"net/http"
+)
+
+const (
+	// Transaction statuses
+	TransactionSuccessful = "successful"
+	TransactionPending    = "pending"
+)
+
+// TransactionRequest adds a player, drops a player or both. Adding a player
+// on waivers files a waiver claim, with FAABBid as the bid in FAAB leagues.
+type TransactionRequest struct {
+	Add     string
+	Drop    string
+	FAABBid *int
+}
+
+// Type returns the Yahoo transaction type for the request.
+func (r TransactionRequest) Type() string {
+	switch {
+	case r.Add != "" && r.Drop != "":
+		return TransactionAddDrop
+	case r.Add != "":
+		return TransactionAdd
+	}
+	return TransactionDrop
+}
+
+// Transact submits an add, drop, add/drop or waiver claim for the team and
+// returns the resulting transaction, whose status is pending for waiver
+// claims. Adding a player once the weekly add limit is used up is refused
+// with ErrAddLimit, and dropping an undroppable player or a keeper with
+// ErrUndroppable, before anything is sent.
+func (yc *YahooClient) Transact(teamKey string, r TransactionRequest) (Transaction, error) {
+	if r.Add == "" && r.Drop == "" {
+		return Transaction{}, errors.New("transaction needs a player to add or drop")
+	}
+	if r.Add != "" {
+		budget, err := yc.GetAddBudget(teamKey)
+		if err != nil {
+			return Transaction{}, err
+		}
+		if !budget.CanAdd() {
+			return Transaction{}, fmt.Errorf("%w: %s", ErrAddLimit, budget)
+		}
+	}
+	if r.Drop != "" {
+		if err := yc.checkDroppable(teamKey, r.Drop); err != nil {
+			return Transaction{}, err
+		}
+	}
+
+	var players []AddDropPlayer
+	if r.Add != "" {
+		var addPlayer AddDropPlayer
+		addPlayer.PlayerKey = r.Add
+		addPlayer.TransactionData.Type = TransactionAdd
+		addPlayer.TransactionData.DestinationTeamKey = teamKey
+		players = append(players, addPlayer)
+	}
+	if r.Drop != "" {
+		var dropPlayer AddDropPlayer
+		dropPlayer.PlayerKey = r.Drop
+		dropPlayer.TransactionData.Type = TransactionDrop
+		dropPlayer.TransactionData.SourceTeamKey = teamKey
+		players = append(players, dropPlayer)
+	}
+
+	yahooURL := yc.BaseURL + "/league/" + LeagueKey(teamKey) + "/transactions"
+
+	entry := audit.Entry{Type: r.Type(), TeamKey: teamKey}
+	for _, p := range players {
+		entry.Players = append(entry.Players, audit.Player{Key: p.PlayerKey, Detail: p.TransactionData.Type})
+	}
+
+	respBody, err := yc.sendXMLRequest(http.MethodPost, yahooURL, transactionBody(r, players))
+	if err != nil {
+		yc.audit(entry, err)
+		email.Notify(yc.EnableEmail, respBody)
+		log.Printf("Failed to %s players: %v", r.Type(), err)
+		return Transaction{}, err
+	}
+
+	var fantasyContent FantasyContent
+	if err := xml.Unmarshal(respBody, &fantasyContent); err != nil {
+		yc.audit(entry, err)
+		log.Println("Error unmarshaling XML:", err)
+		return Transaction{}, err
+	}
+	tr := fantasyContent.Transaction
+	entry.TransactionKey, entry.Status = tr.TransactionKey, tr.Status
+	for i, p := range entry.Players {
+		for _, tp := range tr.Players.Player {
+			if tp.PlayerKey == p.Key {
+				entry.Players[i].Name = tp.Name.Full
+			}
+		}
+	}
+	yc.audit(entry, nil)
+	log.Printf("Transaction %s (%s): %s", tr.TransactionKey, tr.Type, tr.Status)
+	return tr, nil
+}
+
+// transactionBody builds the request body for the transaction type: Yahoo
+// takes an add/drop's players under <players> but the single player of an
+// add or drop directly under <transaction>.
+func transactionBody(r TransactionRequest, players []AddDropPlayer) interface{} {
+	if len(players) == 1 {
+		var body SinglePlayerRequest
+		body.Transaction.Type = r.Type()
+		body.Transaction.FAABBid = r.FAABBid
+		body.Transaction.Player = players[0]
+		return body
+	}
+	var body AddDropPlayerRequest
+	body.Transaction.Type = r.Type()
+	body.Transaction.FAABBid = r.FAABBid
+	body.Transaction.Players.AddDropPlayer = players
+	return body
+}
+
+// checkDroppable refuses players that are not on the roster, undroppable or
+// kept.
+func (yc *YahooClient) checkDroppable(teamKey string, playerKey string) error {
+	roster, err := yc.GetRosterPlayers(teamKey, DateCoverage(clock.Today(yc.Clock)))
+	if err != nil {
+		return err
+	}
+	for _, p := range roster.PlayerList {
+		if p.PlayerKey != playerKey {
+			continue
+		}
+		if p.IsUndroppable == 1 {
+			return fmt.Errorf("%w: %s is undroppable", ErrUndroppable, p.Name.Full)
+		}
+		if !p.IsDroppable() {
+			return fmt.Errorf("%w: %s is a keeper", ErrUndroppable, p.Name.Full)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s is not on the roster of %s", playerKey, teamKey)
+}
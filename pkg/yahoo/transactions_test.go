@@ -0,0 +1,80 @@
+package yahoo_test
+
+import (
+	"encoding/xml"
+	"hockey-hacks/pkg/fakeServer"
+	"hockey-hacks/pkg/yahoo"
+	"net/http"
+	"testing"
+)
+
+// transactionXML is the shape of a transaction request body, accepting the
+// player either directly under <transaction> or under <players>.
+type transactionXML struct {
+	Transaction struct {
+		Type    string                `xml:"type"`
+		FAABBid *int                  `xml:"faab_bid"`
+		Player  []yahoo.AddDropPlayer `xml:"player"`
+		Players *struct {
+			Player []yahoo.AddDropPlayer `xml:"player"`
+		} `xml:"players"`
+	} `xml:"transaction"`
+}
+
+func TestTransactBodies(t *testing.T) {
+	bid := 7
+	tests := []struct {
+		name       string
+		req        yahoo.TransactionRequest
+		wantType   string
+		wantNested bool
+		wantKeys   []string
+	}{
+		{"add", yahoo.TransactionRequest{Add: "465.p.8870"}, yahoo.TransactionAdd, false, []string{"465.p.8870"}},
+		{"claim", yahoo.TransactionRequest{Add: "465.p.8870", FAABBid: &bid}, yahoo.TransactionAdd, false, []string{"465.p.8870"}},
+		{"drop", yahoo.TransactionRequest{Drop: "465.p.4718"}, yahoo.TransactionDrop, false, []string{"465.p.4718"}},
+		{"add/drop", yahoo.TransactionRequest{Add: "465.p.8870", Drop: "465.p.4718"}, yahoo.TransactionAddDrop, true, []string{"465.p.8870", "465.p.4718"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := fakeServer.New()
+			defer s.Close()
+			if _, err := s.YahooClient().Transact("465.l.1234.t.8", tt.req); err != nil {
+				t.Fatal(err)
+			}
+
+			posts := s.RequestsTo(http.MethodPost, "/league/465.l.1234/transactions")
+			if len(posts) != 1 {
+				t.Fatalf("got %d transaction POSTs, want 1", len(posts))
+			}
+			var body transactionXML
+			if err := xml.Unmarshal(posts[0].Body, &body); err != nil {
+				t.Fatal(err)
+			}
+			tr := body.Transaction
+			if tr.Type != tt.wantType {
+				t.Errorf("type = %q, want %q", tr.Type, tt.wantType)
+			}
+			if (tr.FAABBid != nil) != (tt.req.FAABBid != nil) {
+				t.Errorf("faab_bid = %v, want %v", tr.FAABBid, tt.req.FAABBid)
+			}
+			players := tr.Player
+			if tt.wantNested {
+				if tr.Players == nil || len(tr.Player) != 0 {
+					t.Fatalf("want players under <players> only:\n%s", posts[0].Body)
+				}
+				players = tr.Players.Player
+			} else if tr.Players != nil {
+				t.Fatalf("want a single <player> under <transaction>:\n%s", posts[0].Body)
+			}
+			if len(players) != len(tt.wantKeys) {
+				t.Fatalf("got %d players, want %d", len(players), len(tt.wantKeys))
+			}
+			for i, key := range tt.wantKeys {
+				if players[i].PlayerKey != key {
+					t.Errorf("player %d = %s, want %s", i, players[i].PlayerKey, key)
+				}
+			}
+		})
+	}
+}
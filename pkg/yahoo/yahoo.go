@@ -3,19 +3,15 @@ package yahoo
 
 import (
 	"bytes"
-	"encoding/base64"
-	"encoding/json"
 	"encoding/xml"
+	"hockey-hacks/pkg/audit"
+	"hockey-hacks/pkg/clock"
 	"hockey-hacks/pkg/email"
-	"hockey-hacks/pkg/sportsData"
 	"io"
 	"log"
 	"net/http"
-	"net/url"
-	"os"
 	"strings"
 	"sync"
-	"time"
 
 	"golang.org/x/oauth2/endpoints"
 )
@@ -26,6 +22,15 @@ const (
 	// Position constants
 	PositionGoalie = "G"
 	PositionBench  = "BN"
+	PositionIR     = "IR"
+	PositionIRPlus = "IR+"
+	PositionNA     = "NA"
+	PositionUtil   = "Util"
+	PositionFwd    = "F"
+
+	// DefaultGoalieSlots is the number of active goalie slots in a
+	// standard Yahoo lineup
+	DefaultGoalieSlots = 2
 
 	// Transaction constants
 	TransactionAddDrop = "add/drop"
@@ -36,166 +41,108 @@ const (
 type YahooClient struct {
 	Auth        YahooAuth
 	EnableEmail bool
+
+	// TokenStore persists the token between runs
+	TokenStore TokenStore
+	// Audit records every request that changes a roster, nil to disable
+	Audit  *audit.Log
+	Clock  clock.Clock
+	authMu sync.Mutex
+
+	// HTTPClient, BaseURL and TokenURL can be replaced to talk to a fake
+	// server in tests.
+	HTTPClient *http.Client
+	BaseURL    string
+	TokenURL   string
 }
 
 func NewYahooClient(enableEmail bool) *YahooClient {
 	return &YahooClient{
 		EnableEmail: enableEmail,
+		TokenStore:  &FileTokenStore{Path: TokenPath()},
+		Audit:       audit.New(audit.Path()),
+		Clock:       clock.Real{},
+		HTTPClient:  &http.Client{},
+		BaseURL:     YahooFantasyAPIBaseURL,
+		TokenURL:    endpoints.Yahoo.TokenURL,
 	}
 }
 
-func (yc *YahooClient) RefreshAuth(wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	tok := base64.StdEncoding.EncodeToString([]byte(os.Getenv("YAHOO_CLIENT_ID") + ":" + os.Getenv("YAHOO_CLIENT_SECRET")))
-
-	data := url.Values{}
-	data.Set("grant_type", "refresh_token")
-	data.Set("redirect_uri", "oob")
-	data.Set("refresh_token", os.Getenv("YAHOO_REFRESH_TOKEN"))
-
-	client := http.Client{}
-	req, err := http.NewRequest(http.MethodPost, endpoints.Yahoo.TokenURL, strings.NewReader(data.Encode()))
-	if err != nil {
-		log.Fatalln(err)
-	}
-	req.Header = http.Header{
-		"Authorization": {"Basic " + tok},
-		"Content-Type":  {"application/x-www-form-urlencoded"},
-	}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatalln(err)
-	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatalln(err)
+// LeagueKey returns the league part of a team key, e.g. 465.l.1234 for
+// 465.l.1234.t.8.
+func LeagueKey(teamKey string) string {
+	if i := strings.Index(teamKey, ".t."); i != -1 {
+		return teamKey[:i]
 	}
+	return teamKey
+}
 
-	if resp.StatusCode == 200 {
-		json.Unmarshal([]byte(body), &yc.Auth)
-	} else {
-		if yc.EnableEmail {
-			email.SendEmail(body)
-		}
-		log.Fatalf("Yahoo Auth Failed: %s", string(body))
-		os.Exit(1)
+// GetRosterPlayers returns the team's roster with each player's selected
+// position for the given date or week.
+func (yc *YahooClient) GetRosterPlayers(teamKey string, coverage Coverage) (Players, error) {
+	team, err := yc.GetTeamRoster(teamKey, coverage)
+	if err != nil {
+		return Players{}, err
 	}
+	return team.Roster.Players, nil
 }
 
-func (yc *YahooClient) GetRosterPlayers() (Players, error) {
-	url := YahooFantasyAPIBaseURL + "/team/" + os.Getenv("YAHOO_LEAGUE_ID") + ".t." + os.Getenv("YAHOO_TEAM_ID") + "/roster/players"
+// GetTeamRoster returns the team, including its weekly roster adds, with
+// its roster for the given date or week.
+func (yc *YahooClient) GetTeamRoster(teamKey string, coverage Coverage) (Team, error) {
+	url := yc.BaseURL + "/team/" + teamKey + "/roster" + coverage.param() + "/players"
 	respBody, err := yc.sendXMLRequest(http.MethodGet, url, nil)
 
 	if err != nil {
-		if yc.EnableEmail {
-			email.SendEmail(respBody)
-		}
-		log.Fatalln("Failed to get roster players")
-		return Players{}, err
+		email.Notify(yc.EnableEmail, respBody)
+		log.Println("Failed to get roster players:", err)
+		return Team{}, err
 	}
 	var fantasyContent FantasyContent
 	err = xml.Unmarshal([]byte(respBody), &fantasyContent)
 	if err != nil {
 		log.Println("Error unmarshaling XML:", err)
-		return Players{}, err
+		return Team{}, err
 	}
 
-	return fantasyContent.Team.Roster.Players, nil
+	return fantasyContent.Team, nil
 }
 
-func (yc *YahooClient) SwapPlayers(teamGoalies sportsData.Goalies) {
-	var requestBody SwapPlayerRequest
-	requestBody.Roster.CoverageType = "date"
-	requestBody.Roster.Date = time.Now().Format(time.DateOnly)
-
-	// Team 1 Goalies
-	team1G1 := SwapPlayer{PlayerKey: os.Getenv("TEAM1_G1")}
-	team1G2 := SwapPlayer{PlayerKey: os.Getenv("TEAM1_G2")}
-	// Team 2 Goalies
-	team2G1 := SwapPlayer{PlayerKey: os.Getenv("TEAM2_G1")}
-	team2G2 := SwapPlayer{PlayerKey: os.Getenv("TEAM2_G2")}
-
-	team1G1Last := os.Getenv("TEAM1_G1_LASTNAME")
-	team1G2Last := os.Getenv("TEAM1_G2_LASTNAME")
-	team2G1Last := os.Getenv("TEAM2_G1_LASTNAME")
-	team2G2Last := os.Getenv("TEAM2_G2_LASTNAME")
-
-	// Check if we have no starting goalies
-	if len(teamGoalies) == 0 {
-		return
-	}
-
-	// Handle cases based on number of starting goalies
-	if len(teamGoalies) == 1 {
-		// Only one goalie starting - determine which team they're on
-		goalie := teamGoalies[0]
-		team1Abbr := os.Getenv("TEAM1_ABBR")
-		if goalie.Team == team1Abbr {
-			// Team 1 goalie starting, bench team 2
-			if goalie.LastName == team1G1Last {
-				team1G1.Position, team1G2.Position = PositionGoalie, PositionGoalie
-			} else if goalie.LastName == team1G2Last {
-				team1G1.Position, team1G2.Position = PositionGoalie, PositionGoalie
-			}
-			team2G1.Position, team2G2.Position = PositionBench, PositionBench
-		} else {
-			// Team 2 goalie starting, bench team 1
-			if goalie.LastName == team2G1Last {
-				team2G1.Position, team2G2.Position = PositionGoalie, PositionGoalie
-			} else if goalie.LastName == team2G2Last {
-				team2G1.Position, team2G2.Position = PositionGoalie, PositionGoalie
-			}
-			team1G1.Position, team1G2.Position = PositionBench, PositionBench
-		}
-	} else if len(teamGoalies) >= 2 {
-		// Both teams have starting goalies
-		for _, goalie := range teamGoalies {
-			team1Abbr := os.Getenv("TEAM1_ABBR")
-			if goalie.Team == team1Abbr {
-				if goalie.LastName == team1G1Last {
-					team1G1.Position, team1G2.Position = PositionGoalie, PositionBench
-				} else if goalie.LastName == team1G2Last {
-					team1G1.Position, team1G2.Position = PositionBench, PositionGoalie
-				}
-			} else {
-				if goalie.LastName == team2G1Last {
-					team2G1.Position, team2G2.Position = PositionGoalie, PositionBench
-				} else if goalie.LastName == team2G2Last {
-					team2G1.Position, team2G2.Position = PositionBench, PositionGoalie
-				}
-			}
-		}
+// SetLineup moves the given players into their new positions for the date
+// or week.
+// Players already in their new position are left out of the request, and
+// nothing is sent when no player moves. It returns the moves that were made.
+func (yc *YahooClient) SetLineup(teamKey string, coverage Coverage, moves []Move) ([]Move, error) {
+	moves = Changes(moves)
+	if len(moves) == 0 {
+		log.Println("Lineup already set, no moves to make")
+		return nil, nil
 	}
 
-	requestBody.Roster.Players.Player = []SwapPlayer{team2G1, team2G2, team1G1, team1G2}
-
-	yahooURL := YahooFantasyAPIBaseURL + "/team/" + os.Getenv("YAHOO_LEAGUE_ID") + ".t." + os.Getenv("YAHOO_TEAM_ID") + "/roster"
-
-	yc.sendXMLRequest(http.MethodPut, yahooURL, requestBody)
-}
-
-func (yc *YahooClient) addDrop(add string, drop string) {
-	var requestBody AddDropPlayerRequest
-	requestBody.Transaction.Type = TransactionAddDrop
-
-	var addPlayer AddDropPlayer
-	addPlayer.PlayerKey = add
-	addPlayer.TransactionData.Type = TransactionAdd
-	addPlayer.TransactionData.DestinationTeamKey = os.Getenv("YAHOO_LEAGUE_ID") + ".t." + os.Getenv("YAHOO_TEAM_ID")
+	requestBody := NewSwapPlayerRequest(coverage, moves)
 
-	var dropPlayer AddDropPlayer
-	dropPlayer.PlayerKey = drop
-	dropPlayer.TransactionData.Type = TransactionDrop
-	dropPlayer.TransactionData.SourceTeamKey = os.Getenv("YAHOO_LEAGUE_ID") + ".t." + os.Getenv("YAHOO_TEAM_ID")
+	yahooURL := yc.BaseURL + "/team/" + teamKey + "/roster"
 
-	requestBody.Transaction.Players.AddDropPlayer = []AddDropPlayer{addPlayer, dropPlayer}
-
-	yahooURL := YahooFantasyAPIBaseURL + "/league/" + os.Getenv("YAHOO_LEAGUE_ID") + "/transactions"
+	respBody, err := yc.sendXMLRequest(http.MethodPut, yahooURL, requestBody)
+	entry := audit.Entry{Type: "lineup " + coverage.String(), TeamKey: teamKey}
+	for _, m := range moves {
+		entry.Players = append(entry.Players, audit.Player{Key: m.PlayerKey, Name: m.Name, Detail: m.Current + " -> " + m.Position})
+	}
+	yc.audit(entry, err)
+	if err != nil {
+		email.Notify(yc.EnableEmail, respBody)
+		log.Println("Failed to set lineup:", err)
+		return nil, err
+	}
 
-	yc.sendXMLRequest(http.MethodPost, yahooURL, requestBody)
+	for _, m := range moves {
+		log.Printf("Moved %s from %s to %s (%s)", m.Name, m.Current, m.Position, m.Reason)
+	}
+	return moves, nil
 }
 
+// sendXMLRequest sends the request with a valid access token. A 401 is
+// retried once after refreshing the token.
 func (yc *YahooClient) sendXMLRequest(method string, url string, requestBody interface{}) ([]byte, error) {
 	w := &bytes.Buffer{}
 	w.Write([]byte(xml.Header))
@@ -203,29 +150,46 @@ func (yc *YahooClient) sendXMLRequest(method string, url string, requestBody int
 	if err := enc.Encode(requestBody); err != nil {
 		return nil, err
 	}
+	payload := w.Bytes()
 
-	client := http.Client{}
-	req, err := http.NewRequest(method, url, w)
-	if err != nil {
-		return nil, err
-	}
-	req.Header = http.Header{
-		"Authorization": {"Bearer " + yc.Auth.AccessToken},
-		"Content-Type":  {"application/xml"},
-	}
 	log.Println(requestBody)
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+	for attempt := 0; ; attempt++ {
+		token, err := yc.accessToken()
+		if err != nil {
+			return nil, err
+		}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+		req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header = http.Header{
+			"Authorization": {"Bearer " + token},
+			"Content-Type":  {"application/xml"},
+		}
+		resp, err := yc.HTTPClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
 
-	log.Println(string(body))
+		log.Println(string(body))
 
-	return body, nil
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			log.Println("Yahoo returned 401, refreshing token and retrying")
+			if err := yc.refreshStale(token); err != nil {
+				return body, err
+			}
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return body, newAPIError(resp, body)
+		}
+		return body, nil
+	}
 }